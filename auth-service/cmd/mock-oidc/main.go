@@ -0,0 +1,312 @@
+// Command mock-oidc is a throwaway OpenID Connect provider for local
+// development. It signs whatever identity is typed into its login form, so
+// it must never be reachable outside a developer machine.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	keyID       = "mock-oidc-1"
+	codeTTL     = time.Minute
+	idTokenTTL  = 5 * time.Minute
+	defaultPort = "9000"
+)
+
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	name          string
+	groups        []string
+	expiresAt     time.Time
+}
+
+type server struct {
+	publicURL    string
+	internalURL  string
+	clientID     string
+	clientSecret string
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]*authorization
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Mock OIDC</title></head>
+<body style="font-family: sans-serif; max-width: 420px; margin: 40px auto">
+<h2>Mock OIDC login</h2>
+<p>Development only. Any identity entered here is signed as-is.</p>
+<form method="post">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{$v}}">
+{{end}}
+<p><label>Email<br><input name="email" type="email" required value="staff@cityconnect.local" style="width:100%"></label></p>
+<p><label>Name<br><input name="name" value="Petugas Kota" style="width:100%"></label></p>
+<p><label>Groups (comma separated)<br><input name="groups" value="dpu-staff" style="width:100%"></label></p>
+<p><button type="submit">Sign in</button></p>
+</form>
+</body>
+</html>`))
+
+func main() {
+	publicURL := strings.TrimRight(envOr("PUBLIC_URL", "http://localhost:9000"), "/")
+	s := &server{
+		publicURL:    publicURL,
+		internalURL:  strings.TrimRight(envOr("INTERNAL_URL", publicURL), "/"),
+		clientID:     envOr("CLIENT_ID", "cityconnect"),
+		clientSecret: envOr("CLIENT_SECRET", "cityconnect-secret"),
+		codes:        make(map[string]*authorization),
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("Failed to generate signing key: %v", err)
+	}
+	s.key = key
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+
+	addr := ":" + envOr("PORT", defaultPort)
+	log.Printf("Mock OIDC provider starting on %s (issuer %s)", addr, s.publicURL)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
+
+// discovery advertises the browser-facing authorize endpoint on the public
+// URL and the back-channel endpoints on the address services use.
+func (s *server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.publicURL,
+		"authorization_endpoint":                s.publicURL + "/authorize",
+		"token_endpoint":                        s.internalURL + "/token",
+		"jwks_uri":                              s.internalURL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+func (s *server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := map[string]string{}
+	for _, k := range []string{"client_id", "redirect_uri", "state", "nonce", "code_challenge", "code_challenge_method", "response_type"} {
+		params[k] = r.Form.Get(k)
+	}
+	if params["client_id"] != s.clientID || params["response_type"] != "code" || params["redirect_uri"] == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	if params["code_challenge"] == "" || params["code_challenge_method"] != "S256" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := loginPage.Execute(w, map[string]interface{}{"Params": params}); err != nil {
+			log.Printf("render login page: %v", err)
+		}
+		return
+	}
+
+	code, err := randomString()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var groups []string
+	for _, g := range strings.Split(r.Form.Get("groups"), ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+
+	s.mu.Lock()
+	s.codes[code] = &authorization{
+		clientID:      params["client_id"],
+		redirectURI:   params["redirect_uri"],
+		nonce:         params["nonce"],
+		codeChallenge: params["code_challenge"],
+		email:         strings.ToLower(strings.TrimSpace(r.Form.Get("email"))),
+		name:          strings.TrimSpace(r.Form.Get("name")),
+		groups:        groups,
+		expiresAt:     time.Now().Add(codeTTL),
+	}
+	s.mu.Unlock()
+
+	redirect, err := url.Parse(params["redirect_uri"])
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	query := redirect.Query()
+	query.Set("code", code)
+	query.Set("state", params["state"])
+	redirect.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		// RFC 6749 form-encodes the credentials before basic auth
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.Form.Get("client_id"), r.Form.Get("client_secret")
+	}
+	if clientID != s.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.clientSecret)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="mock-oidc"`)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.Form.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	auth, ok := s.codes[r.Form.Get("code")]
+	delete(s.codes, r.Form.Get("code"))
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(auth.expiresAt) || auth.clientID != clientID || auth.redirectURI != r.Form.Get("redirect_uri") {
+		tokenError(w, "invalid_grant")
+		return
+	}
+	verifierHash := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(verifierHash[:]) != auth.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	idToken, err := s.signIDToken(auth)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	accessToken, err := randomString()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(idTokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func (s *server) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// signIDToken derives the subject from the email so the same person keeps
+// the same identity across logins.
+func (s *server) signIDToken(auth *authorization) (string, error) {
+	sub := sha256.Sum256([]byte(auth.email))
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss":            s.publicURL,
+		"sub":            hex.EncodeToString(sub[:16]),
+		"aud":            auth.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(idTokenTTL).Unix(),
+		"email":          auth.email,
+		"email_verified": true,
+		"name":           auth.name,
+		"groups":         auth.groups,
+	}
+	if auth.nonce != "" {
+		claims["nonce"] = auth.nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(s.key)
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -9,6 +9,11 @@ type Config struct {
 	Server   ServerConfig   `json:"server"`
 	Database DatabaseConfig `json:"database"`
 	JWT      JWTConfig      `json:"jwt"`
+	Mail     MailConfig     `json:"mail"`
+	Login    LoginConfig    `json:"login"`
+	MFA      MFAConfig      `json:"mfa"`
+	OIDC     OIDCConfig     `json:"oidc"`
+	Services ServicesConfig `json:"services"`
 }
 
 type ServerConfig struct {
@@ -24,8 +29,84 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret          string `json:"secret"`
-	ExpirationHours int    `json:"expiration_hours"`
+	ActiveKeyID        string             `json:"active_kid"`
+	Keys               []SigningKeyConfig `json:"keys"`
+	AccessTokenMinutes int                `json:"access_token_minutes"`
+	RefreshTokenHours  int                `json:"refresh_token_hours"`
+}
+
+// SigningKeyConfig describes one key pair. Only the key matching active_kid
+// signs new tokens; the others stay published in the JWKS so tokens signed
+// before a rotation keep validating until they expire.
+type SigningKeyConfig struct {
+	KeyID          string `json:"kid"`
+	Algorithm      string `json:"algorithm"`
+	PrivateKeyFile string `json:"private_key_file"`
+}
+
+// MailConfig selects the outgoing mail driver: "smtp" for real delivery
+// (or a local sink such as Mailpit) and "log" to just print messages.
+type MailConfig struct {
+	Driver string     `json:"driver"`
+	From   string     `json:"from"`
+	AppURL string     `json:"app_url"`
+	SMTP   SMTPConfig `json:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginConfig struct {
+	MaxFailures     int `json:"max_failures"`
+	LockoutMinutes  int `json:"lockout_minutes"`
+	WindowMinutes   int `json:"window_minutes"`
+	MaxIPFailures   int `json:"max_ip_failures"`
+	BaseDelayMillis int `json:"base_delay_ms"`
+	MaxDelaySeconds int `json:"max_delay_seconds"`
+}
+
+type MFAConfig struct {
+	Issuer            string `json:"issuer"`
+	RequiredForAdmins bool   `json:"required_for_admins"`
+	ChallengeMinutes  int    `json:"challenge_minutes"`
+}
+
+// OIDCConfig enables staff login through an external OpenID Connect
+// provider. discovery_url only needs setting when auth-service reaches the
+// provider under a different address than the public issuer URL.
+type OIDCConfig struct {
+	Enabled           bool              `json:"enabled"`
+	Issuer            string            `json:"issuer"`
+	DiscoveryURL      string            `json:"discovery_url"`
+	ClientID          string            `json:"client_id"`
+	ClientSecret      string            `json:"client_secret"`
+	RedirectURL       string            `json:"redirect_url"`
+	PostLoginRedirect string            `json:"post_login_redirect"`
+	Scopes            []string          `json:"scopes"`
+	RoleClaim         string            `json:"role_claim"`
+	RoleMappings      []OIDCRoleMapping `json:"role_mappings"`
+	DefaultRole       string            `json:"default_role"`
+	SyncRoles         bool              `json:"sync_roles"`
+}
+
+// OIDCRoleMapping grants Role when the role claim contains Value. The first
+// matching entry wins.
+type OIDCRoleMapping struct {
+	Value string `json:"value"`
+	Role  string `json:"role"`
+}
+
+// ServicesConfig locates report-service and notification-service for
+// personal data exports and account erasure. internal_token must match their
+// internal.token setting.
+type ServicesConfig struct {
+	ReportURL       string `json:"report_url"`
+	NotificationURL string `json:"notification_url"`
+	InternalToken   string `json:"internal_token"`
 }
 
 func LoadConfig(path string) (*Config, error) {
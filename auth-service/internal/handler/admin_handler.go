@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AdminHandler struct {
+	authService      *service.AuthService
+	loginGuard       *service.LoginGuard
+	userAdminService *service.UserAdminService
+}
+
+func NewAdminHandler(authService *service.AuthService, loginGuard *service.LoginGuard, userAdminService *service.UserAdminService) *AdminHandler {
+	return &AdminHandler{
+		authService:      authService,
+		loginGuard:       loginGuard,
+		userAdminService: userAdminService,
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	filter := model.UserFilter{
+		Search:     c.Query("search"),
+		Role:       c.Query("role"),
+		Department: c.Query("department"),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	if active := c.Query("active"); active != "" {
+		isActive, err := strconv.ParseBool(active)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active filter"})
+			return
+		}
+		filter.Active = &isActive
+	}
+
+	response, err := h.userAdminService.ListUsers(actorID, filter)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	user, err := h.userAdminService.GetUser(actorID, userID)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req model.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userAdminService.ChangeRole(actorID, userID, &req, c.ClientIP())
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role updated",
+		"user":    user,
+	})
+}
+
+func (h *AdminHandler) DeactivateUser(c *gin.Context) {
+	h.setActive(c, false)
+}
+
+func (h *AdminHandler) ActivateUser(c *gin.Context) {
+	h.setActive(c, true)
+}
+
+func (h *AdminHandler) setActive(c *gin.Context, active bool) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req model.DeactivateUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := h.userAdminService.SetActive(actorID, userID, active, req.Reason, c.ClientIP()); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	message := "Account deactivated"
+	if active {
+		message = "Account activated"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func (h *AdminHandler) ForcePasswordReset(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.userAdminService.ForcePasswordReset(actorID, userID, c.ClientIP()); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset email sent"})
+}
+
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.loginGuard.Unlock(userID, actorID, c.ClientIP()); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlocked"})
+}
+
+func (h *AdminHandler) ListAuditEvents(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	filter := model.AuditFilter{
+		Event:    c.Query("event"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	response, err := h.userAdminService.ListAuditEvents(actorID, filter)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondAdminError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrUnlockNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSelfModification):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
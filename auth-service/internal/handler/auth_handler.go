@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"auth-service/internal/model"
@@ -12,11 +16,17 @@ import (
 )
 
 type AuthHandler struct {
-	authService *service.AuthService
+	authService           *service.AuthService
+	accountService        *service.AccountService
+	serviceAccountService *service.ServiceAccountService
 }
 
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService *service.AuthService, accountService *service.AccountService, serviceAccountService *service.ServiceAccountService) *AuthHandler {
+	return &AuthHandler{
+		authService:           authService,
+		accountService:        accountService,
+		serviceAccountService: serviceAccountService,
+	}
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -26,23 +36,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	validRoles := map[model.Role]bool{
-		model.RoleWarga:              true,
-		model.RoleAdminKebersihan:    true,
-		model.RoleAdminKesehatan:     true,
-		model.RoleAdminInfrastruktur: true,
-	}
-	if !validRoles[req.Role] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
-		return
-	}
-
 	user, err := h.authService.Register(&req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := h.accountService.SendEmailVerification(user); err != nil {
+		log.Printf("register: verification email for %s: %v", user.ID, err)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User registered successfully",
 		"user":    user,
@@ -56,66 +59,199 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	response, err := h.authService.Login(&req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		var throttled *service.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", retryAfterSeconds(throttled))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAccountLocked):
+			c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAccountDeactivated):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-func (h *AuthHandler) Validate(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, model.ValidateResponse{Valid: false})
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req model.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		c.JSON(http.StatusUnauthorized, model.ValidateResponse{Valid: false})
+	response, err := h.authService.CompleteMFALogin(&req, clientInfo(c))
+	if err != nil {
+		respondMFAError(c, err)
 		return
 	}
 
-	response, err := h.authService.ValidateToken(parts[1])
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) LoginMFAEnroll(c *gin.Context) {
+	var req model.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if response.Valid {
-		c.Header("X-User-ID", response.UserID)
-		c.Header("X-User-Role", response.Role)
-		c.Header("X-User-Department", response.Department)
-		c.Header("X-User-Name", response.Name)
+	response, err := h.authService.EnrollDuringLogin(req.ChallengeToken)
+	if err != nil {
+		respondMFAError(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-func (h *AuthHandler) Me(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
+func (h *AuthHandler) Validate(c *gin.Context) {
+	var response *model.ValidateResponse
+	var err error
 
-	if userIDStr == "" {
+	// Machine integrations authenticate with an API key instead of a user token
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		response, err = h.serviceAccountService.ValidateAPIKey(apiKey, c.ClientIP())
+	} else {
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, model.ValidateResponse{Valid: false})
 			return
 		}
 
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := h.authService.ValidateToken(tokenStr)
-		if err != nil || !claims.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, model.ValidateResponse{Valid: false})
+			return
+		}
+
+		response, err = h.authService.ValidateToken(parts[1], c.ClientIP())
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// auth_request only looks at the status code, so invalid or revoked
+	// tokens must not come back as 200
+	if !response.Valid {
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	c.Header("X-User-ID", response.UserID)
+	c.Header("X-User-Role", response.Role)
+	c.Header("X-User-Department", response.Department)
+	c.Header("X-User-Name", response.Name)
+	c.Header("X-User-Permissions", strings.Join(response.Permissions, ","))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Introspect implements RFC 7662 for resource servers outside the gateway.
+// Callers authenticate with HTTP Basic (or client_id/client_secret form
+// fields) using a service account id and an API key scoped
+// token.introspect.
+func (h *AuthHandler) Introspect(c *gin.Context) {
+	clientID, clientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, clientSecret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+
+	err := h.serviceAccountService.AuthenticateClient(clientID, clientSecret, model.PermTokenIntrospect, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidClient):
+			c.Header("WWW-Authenticate", `Basic realm="cityconnect"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		case errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient_scope"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	// token_type_hint needs no handling: access tokens are the only kind
+	// this server introspects, and RFC 7662 says to search past a wrong hint
+	response, err := h.authService.IntrospectToken(token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req model.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.authService.Refresh(req.RefreshToken, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		userIDStr = claims.UserID
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	if err := h.authService.Logout(strings.TrimPrefix(authHeader, "Bearer "), req.RefreshToken); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func (h *AuthHandler) ListRoles(c *gin.Context) {
+	if _, ok := authenticatedUserID(c, h.authService); !ok {
+		return
+	}
+
+	roles, err := h.authService.ListRoles()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+func (h *AuthHandler) Me(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
 		return
 	}
 
@@ -128,6 +264,66 @@ func (h *AuthHandler) Me(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+// authenticatedUserID verifies the bearer token itself instead of trusting
+// X-User-ID, because auth routes are not behind the gateway's auth_request.
+func authenticatedUserID(c *gin.Context, authService *service.AuthService) (uuid.UUID, bool) {
+	userID, _, ok := authenticatedSession(c, authService)
+	return userID, ok
+}
+
+// authenticatedSession is authenticatedUserID that also returns the id of
+// the session the token belongs to.
+func authenticatedSession(c *gin.Context, authService *service.AuthService) (uuid.UUID, uuid.UUID, bool) {
+	token := bearerToken(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	claims, err := authService.ValidateToken(token, c.ClientIP())
+	if err != nil || !claims.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, sessionID, true
+}
+
+func clientInfo(c *gin.Context) model.ClientInfo {
+	return model.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+func bearerToken(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return ""
+	}
+	return strings.TrimPrefix(authHeader, "Bearer ")
+}
+
 func (h *AuthHandler) Health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 }
+
+func retryAfterSeconds(err *service.LoginThrottledError) string {
+	return strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds())))
+}
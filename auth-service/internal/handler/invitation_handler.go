@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	invitationService *service.InvitationService
+	authService       *service.AuthService
+}
+
+func NewInvitationHandler(invitationService *service.InvitationService, authService *service.AuthService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+		authService:       authService,
+	}
+}
+
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.invitationService.CreateInvitation(userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvitationNotAllowed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.invitationService.ListInvitations(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
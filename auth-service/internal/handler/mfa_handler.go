@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaService  *service.MFAService
+	authService *service.AuthService
+}
+
+func NewMFAHandler(mfaService *service.MFAService, authService *service.AuthService) *MFAHandler {
+	return &MFAHandler{
+		mfaService:  mfaService,
+		authService: authService,
+	}
+}
+
+func (h *MFAHandler) Status(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	status, err := h.mfaService.Status(userID)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	response, err := h.mfaService.Enroll(userID)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *MFAHandler) Activate(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.Activate(userID, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Disable(userID, req.Code); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func respondMFAError(c *gin.Context, err error) {
+	var throttled *service.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", retryAfterSeconds(throttled))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAccountLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidMFACode), errors.Is(err, service.ErrInvalidMFAChallenge):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFARequired), errors.Is(err, service.ErrAccountDeactivated):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCHandler struct {
+	oidcService *service.OIDCService
+}
+
+func NewOIDCHandler(oidcService *service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+func (h *OIDCHandler) Login(c *gin.Context) {
+	redirectURL, err := h.oidcService.LoginURL()
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// Callback always redirects back to the frontend; failures travel as an
+// error query parameter so the user lands on a page rather than raw JSON.
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	redirectURL := h.oidcService.Callback(c.Query("code"), c.Query("state"), c.Query("error"))
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+func (h *OIDCHandler) Token(c *gin.Context) {
+	var req model.OIDCTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.oidcService.ExchangeLoginCode(req.Code, clientInfo(c))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOIDCDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAccountDeactivated):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOIDCInvalidCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
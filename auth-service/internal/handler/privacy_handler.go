@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyHandler struct {
+	authService    *service.AuthService
+	privacyService *service.PrivacyService
+}
+
+func NewPrivacyHandler(authService *service.AuthService, privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		authService:    authService,
+		privacyService: privacyService,
+	}
+}
+
+// Export downloads the user's data as one JSON document, or with
+// ?format=zip as a ZIP archive holding one JSON file per section.
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or zip"})
+		return
+	}
+
+	export, err := h.privacyService.Export(userID)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "could not collect account data, please try again later"})
+		log.Printf("export for %s: %v", userID, err)
+		return
+	}
+
+	filename := fmt.Sprintf("cityconnect-data-%s", export.ExportedAt.Format("20060102"))
+	c.Header("Cache-Control", "no-store")
+
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		c.IndentedJSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+	if err := writeExportZip(c.Writer, export); err != nil {
+		log.Printf("export for %s: write zip: %v", userID, err)
+	}
+}
+
+func (h *PrivacyHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.privacyService.DeleteAccount(userID, &req, c.ClientIP()); err != nil {
+		var throttled *service.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", retryAfterSeconds(throttled))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidCurrentPassword),
+			errors.Is(err, service.ErrAccountDeletionNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			log.Printf("delete account %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete account, please try again later"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted"})
+}
+
+func writeExportZip(w http.ResponseWriter, export *model.AccountExport) error {
+	sections := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"sessions.json", export.Sessions},
+		{"linked_identities.json", export.LinkedIdentities},
+		{"account_activity.json", export.AccountActivity},
+		{"reports.json", export.Reports},
+		{"votes.json", export.Votes},
+		{"comments.json", export.Comments},
+		{"notifications.json", export.Notifications},
+	}
+
+	archive := zip.NewWriter(w)
+	for _, section := range sections {
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     section.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ProfileHandler struct {
+	authService    *service.AuthService
+	profileService *service.ProfileService
+}
+
+func NewProfileHandler(authService *service.AuthService, profileService *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		authService:    authService,
+		profileService: profileService,
+	}
+}
+
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.profileService.UpdateProfile(userID, bearerToken(c), &req, c.ClientIP())
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ProfileHandler) ChangePassword(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.profileService.ChangePassword(userID, &req, clientInfo(c))
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ProfileHandler) ListSessions(c *gin.Context) {
+	userID, sessionID, ok := authenticatedSession(c, h.authService)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(userID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+func (h *ProfileHandler) RevokeSession(c *gin.Context) {
+	userID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session signed out"})
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	var throttled *service.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", retryAfterSeconds(throttled))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidCurrentPassword):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmailInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmptyName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ServiceAccountHandler struct {
+	authService           *service.AuthService
+	serviceAccountService *service.ServiceAccountService
+}
+
+func NewServiceAccountHandler(authService *service.AuthService, serviceAccountService *service.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		authService:           authService,
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+func (h *ServiceAccountHandler) CreateServiceAccount(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req model.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.serviceAccountService.CreateServiceAccount(actorID, &req, c.ClientIP())
+	if err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+func (h *ServiceAccountHandler) ListServiceAccounts(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.serviceAccountService.ListServiceAccounts(actorID)
+	if err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"service_accounts": accounts})
+}
+
+func (h *ServiceAccountHandler) CreateAPIKey(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service account ID"})
+		return
+	}
+
+	var req model.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.serviceAccountService.CreateAPIKey(actorID, accountID, &req, c.ClientIP())
+	if err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *ServiceAccountHandler) ListAPIKeys(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service account ID"})
+		return
+	}
+
+	keys, err := h.serviceAccountService.ListAPIKeys(actorID, accountID)
+	if err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+func (h *ServiceAccountHandler) RevokeAPIKey(c *gin.Context) {
+	actorID, ok := authenticatedUserID(c, h.authService)
+	if !ok {
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service account ID"})
+		return
+	}
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key ID"})
+		return
+	}
+
+	if err := h.serviceAccountService.RevokeAPIKey(actorID, accountID, keyID, c.ClientIP()); err != nil {
+		respondServiceAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+func respondServiceAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrServiceAccountNotFound), errors.Is(err, service.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNameInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidDepartment), errors.Is(err, service.ErrUnknownScope):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,15 @@
+package mailer
+
+import "log"
+
+// LogMailer prints messages instead of sending them, for development.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	log.Printf("mail: to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
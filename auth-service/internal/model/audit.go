@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEventType string
+
+const (
+	AuditAccountLocked   AuditEventType = "account_locked"
+	AuditAccountUnlocked AuditEventType = "account_unlocked"
+	AuditMFAEnabled      AuditEventType = "mfa_enabled"
+	AuditMFADisabled     AuditEventType = "mfa_disabled"
+	AuditRoleChanged     AuditEventType = "user_role_changed"
+	AuditUserDeactivated AuditEventType = "user_deactivated"
+	AuditUserActivated   AuditEventType = "user_activated"
+	AuditPasswordReset   AuditEventType = "user_password_reset_forced"
+	AuditPasswordChanged AuditEventType = "password_changed"
+	AuditEmailChanged    AuditEventType = "email_changed"
+
+	AuditServiceAccountCreated AuditEventType = "service_account_created"
+	AuditAPIKeyCreated         AuditEventType = "api_key_created"
+	AuditAPIKeyRevoked         AuditEventType = "api_key_revoked"
+
+	AuditOIDCLinked AuditEventType = "oidc_identity_linked"
+
+	AuditAccountDeleted AuditEventType = "account_deleted"
+)
+
+type AuditEvent struct {
+	ID        uuid.UUID              `json:"id"`
+	Event     AuditEventType         `json:"event"`
+	UserID    *uuid.UUID             `json:"user_id,omitempty"`
+	ActorID   *uuid.UUID             `json:"actor_id,omitempty"`
+	Email     string                 `json:"email,omitempty"`
+	IPAddress string                 `json:"ip_address,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type AuditFilter struct {
+	UserID   *uuid.UUID
+	Event    string
+	Page     int
+	PageSize int
+}
+
+type AuditListResponse struct {
+	Events   []AuditEvent `json:"events"`
+	Total    int          `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Invitation struct {
+	ID         uuid.UUID  `json:"id"`
+	CodeHash   string     `json:"-"`
+	Role       Role       `json:"role"`
+	Department *string    `json:"department,omitempty"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+	UsedBy     *uuid.UUID `json:"used_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateInvitationRequest struct {
+	Role       Role   `json:"role" binding:"required"`
+	Department string `json:"department"`
+	ExpiresIn  int    `json:"expires_in_hours"`
+}
+
+type CreateInvitationResponse struct {
+	Code       string     `json:"code"`
+	Invitation Invitation `json:"invitation"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OIDCLoginState struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+type UserIdentity struct {
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	UserID      uuid.UUID `json:"user_id"`
+	Email       string    `json:"email,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// OIDCTokenRequest exchanges the one-time code handed to the frontend after
+// the provider callback for CityConnect tokens.
+type OIDCTokenRequest struct {
+	Code string `json:"code" binding:"required"`
+}
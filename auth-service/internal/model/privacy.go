@@ -0,0 +1,25 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AccountExport is everything CityConnect holds about a user, gathered from
+// all three services for a personal data request. Reports, votes, comments
+// and notifications are passed through as the owning service returned them.
+type AccountExport struct {
+	ExportedAt       time.Time       `json:"exported_at"`
+	Profile          *User           `json:"profile"`
+	Sessions         []Session       `json:"sessions"`
+	LinkedIdentities []UserIdentity  `json:"linked_identities"`
+	AccountActivity  []AuditEvent    `json:"account_activity"`
+	Reports          json.RawMessage `json:"reports"`
+	Votes            json.RawMessage `json:"votes"`
+	Comments         json.RawMessage `json:"comments"`
+	Notifications    json.RawMessage `json:"notifications"`
+}
+
+type DeleteAccountRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
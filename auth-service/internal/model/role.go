@@ -0,0 +1,34 @@
+package model
+
+// Permissions checked inside auth-service. Other services check their own
+// permission names against the list /validate returns.
+const (
+	PermUserInvite = "user.invite"
+	PermUserUnlock = "user.unlock"
+	PermUserRead   = "user.read"
+	PermUserManage = "user.manage"
+	PermAuditRead  = "audit.read"
+
+	PermServiceAccountManage = "service_account.manage"
+
+	// PermTokenIntrospect is only meant as an API key scope; it lets the
+	// key's service account call /introspect.
+	PermTokenIntrospect = "token.introspect"
+)
+
+type RoleDefinition struct {
+	Name        Role     `json:"name"`
+	Description string   `json:"description"`
+	Department  *string  `json:"department,omitempty"`
+	IsAdmin     bool     `json:"is_admin"`
+	Permissions []string `json:"permissions"`
+}
+
+func (r *RoleDefinition) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
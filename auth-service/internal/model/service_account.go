@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAccountRole is reported as X-User-Role for requests authenticated
+// with an API key. It is not a row in the roles table.
+const ServiceAccountRole = "service_account"
+
+type ServiceAccount struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Department  *string    `json:"department,omitempty"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Keys        []APIKey   `json:"keys"`
+}
+
+type APIKey struct {
+	ID               uuid.UUID  `json:"id"`
+	ServiceAccountID uuid.UUID  `json:"service_account_id"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	KeyHash          string     `json:"-"`
+	Scopes           []string   `json:"scopes"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP       string     `json:"last_used_ip,omitempty"`
+	CreatedBy        *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreateServiceAccountRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	Department  string `json:"department"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"`
+}
+
+// CreateAPIKeyResponse is the only time the plaintext key is returned.
+type CreateAPIKeyResponse struct {
+	Key    string  `json:"key"`
+	APIKey *APIKey `json:"api_key"`
+}
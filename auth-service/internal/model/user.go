@@ -8,29 +8,44 @@ import (
 
 type Role string
 
-const (
-	RoleWarga           Role = "warga"
-	RoleAdminKebersihan Role = "admin_kebersihan"
-	RoleAdminKesehatan  Role = "admin_kesehatan"
-	RoleAdminInfrastruktur Role = "admin_infrastruktur"
-)
+// DefaultRole is given to every self-registered account. All other roles,
+// their departments and permissions live in the roles tables.
+const DefaultRole Role = "warga"
 
 type User struct {
-	ID           uuid.UUID  `json:"id"`
-	Email        string     `json:"email"`
-	PasswordHash string     `json:"-"` 
-	Name         string     `json:"name"`
-	Role         Role       `json:"role"`
-	Department   *string    `json:"department,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID              uuid.UUID  `json:"id"`
+	Email           string     `json:"email"`
+	PasswordHash    string     `json:"-"`
+	Name            string     `json:"name"`
+	Role            Role       `json:"role"`
+	Department      *string    `json:"department,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	PendingEmail    *string    `json:"pending_email,omitempty"`
+	LockedUntil     *time.Time `json:"locked_until,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	DeactivatedAt   *time.Time `json:"deactivated_at,omitempty"`
+	Permissions     []string   `json:"permissions"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 type RegisterRequest struct {
 	Email      string `json:"email" binding:"required,email"`
 	Password   string `json:"password" binding:"required,min=6"`
 	Name       string `json:"name" binding:"required"`
-	Role       Role   `json:"role" binding:"required"`
-	Department string `json:"department"`
+	InviteCode string `json:"invite_code"`
+}
+
+// UpdateProfileRequest changes only the fields that are present. A new email
+// needs the current password and only replaces the old one once confirmed.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" binding:"omitempty,min=1"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
 }
 
 type LoginRequest struct {
@@ -38,15 +53,42 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse either carries the issued tokens or, when the account uses
+// MFA, only a challenge token to be exchanged at /login/mfa.
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token                 string   `json:"token,omitempty"`
+	RefreshToken          string   `json:"refresh_token,omitempty"`
+	ExpiresIn             int64    `json:"expires_in,omitempty"`
+	User                  *User    `json:"user,omitempty"`
+	MFARequired           bool     `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool     `json:"mfa_enrollment_required,omitempty"`
+	ChallengeToken        string   `json:"challenge_token,omitempty"`
+	RecoveryCodes         []string `json:"recovery_codes,omitempty"`
 }
 
 type ValidateResponse struct {
-	Valid      bool   `json:"valid"`
-	UserID     string `json:"user_id"`
-	Role       string `json:"role"`
-	Department string `json:"department"`
-	Name       string `json:"name"`
+	Valid       bool     `json:"valid"`
+	UserID      string   `json:"user_id"`
+	Role        string   `json:"role"`
+	Department  string   `json:"department"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions,omitempty"`
+	SessionID   string   `json:"session_id,omitempty"`
+}
+
+// IntrospectionResponse follows RFC 7662. Inactive tokens carry nothing but
+// active: false.
+type IntrospectionResponse struct {
+	Active     bool   `json:"active"`
+	Scope      string `json:"scope,omitempty"`
+	TokenType  string `json:"token_type,omitempty"`
+	Username   string `json:"username,omitempty"`
+	Sub        string `json:"sub,omitempty"`
+	Exp        int64  `json:"exp,omitempty"`
+	Iat        int64  `json:"iat,omitempty"`
+	Jti        string `json:"jti,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Department string `json:"department,omitempty"`
+	Name       string `json:"name,omitempty"`
+	SessionID  string `json:"sid,omitempty"`
 }
@@ -0,0 +1,26 @@
+package model
+
+type UserFilter struct {
+	Search     string
+	Role       string
+	Department string
+	Active     *bool
+	Page       int
+	PageSize   int
+}
+
+type UserListResponse struct {
+	Users    []User `json:"users"`
+	Total    int    `json:"total"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type ChangeRoleRequest struct {
+	Role       Role   `json:"role" binding:"required"`
+	Department string `json:"department"`
+}
+
+type DeactivateUserRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,215 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	keyCacheTTL = 10 * time.Minute
+	// Unknown kids trigger a refetch, but never more often than this so
+	// forged tokens can't make us hammer the provider.
+	minRefetchInterval = 30 * time.Second
+)
+
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+type cachedKey struct {
+	keyType   string
+	algorithm string
+	publicKey interface{}
+}
+
+// keySet caches the provider's published signing keys.
+type keySet struct {
+	client      *http.Client
+	url         func() (string, error)
+	mu          sync.RWMutex
+	keys        map[string]cachedKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func (s *keySet) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok, stale := s.lookup(kid)
+	if !ok || stale {
+		// On a failed refresh we keep verifying with the last known keys
+		if err := s.refresh(); err != nil {
+			log.Printf("oidc: refresh signing keys: %v", err)
+		}
+		key, ok, _ = s.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+	}
+
+	if !key.allows(token.Method.Alg()) {
+		return nil, errors.New("invalid signing method")
+	}
+	return key.publicKey, nil
+}
+
+// lookup finds a key by kid. Providers with a single key may leave kid out
+// of the token header, in which case the only key is used.
+func (s *keySet) lookup(kid string) (cachedKey, bool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stale := time.Since(s.fetchedAt) > keyCacheTTL
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true, stale
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok, stale
+}
+
+func (s *keySet) refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.lastAttempt) < minRefetchInterval {
+		return nil
+	}
+	s.lastAttempt = time.Now()
+
+	url, err := s.url()
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	keys := make(map[string]cachedKey)
+	for _, k := range body.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		publicKey, err := parseJWK(k)
+		if err != nil {
+			log.Printf("oidc: skipping key %s: %v", k.KeyID, err)
+			continue
+		}
+		keys[k.KeyID] = cachedKey{keyType: k.KeyType, algorithm: k.Algorithm, publicKey: publicKey}
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// allows checks the token's alg against the key. Keys that don't pin an alg
+// accept any algorithm of their own family, never HMAC.
+func (k cachedKey) allows(alg string) bool {
+	if k.algorithm != "" {
+		return alg == k.algorithm
+	}
+	switch k.keyType {
+	case "RSA":
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case "EC":
+		return strings.HasPrefix(alg, "ES")
+	case "OKP":
+		return alg == "EdDSA"
+	}
+	return false
+}
+
+func parseJWK(k jwk) (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"auth-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var ErrTokenNotFound = errors.New("token not found")
+
+type AccountTokenRepository struct {
+	db *sql.DB
+}
+
+func NewAccountTokenRepository(db *sql.DB) *AccountTokenRepository {
+	return &AccountTokenRepository{db: db}
+}
+
+// Create stores a new token and invalidates any earlier unused token for the
+// same user and purpose, so only the most recent email link works.
+func (r *AccountTokenRepository) Create(token *model.AccountToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE account_tokens SET used_at = NOW()
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, token.UserID, token.Purpose)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO account_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`,
+		token.ID,
+		token.UserID,
+		token.Purpose,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ConsumeInTransaction marks a valid token as used and returns it. Expired,
+// used or wrong-purpose tokens are reported as not found.
+func (r *AccountTokenRepository) ConsumeInTransaction(tx *sql.Tx, tokenHash string, purpose model.TokenPurpose) (*model.AccountToken, error) {
+	query := `
+		UPDATE account_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, purpose, expires_at, created_at
+	`
+	token := &model.AccountToken{TokenHash: tokenHash}
+	err := tx.QueryRow(query, tokenHash, purpose).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// FindValid looks a token up without consuming it.
+func (r *AccountTokenRepository) FindValid(tokenHash string, purpose model.TokenPurpose) (*model.AccountToken, error) {
+	query := `
+		SELECT id, user_id, purpose, expires_at, created_at
+		FROM account_tokens
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+	`
+	token := &model.AccountToken{TokenHash: tokenHash}
+	err := r.db.QueryRow(query, tokenHash, purpose).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// DeleteUnused drops a user's outstanding tokens for one purpose, so that
+// only the most recently issued link keeps working.
+func (r *AccountTokenRepository) DeleteUnused(userID uuid.UUID, purpose model.TokenPurpose) error {
+	query := `DELETE FROM account_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`
+	_, err := r.db.Exec(query, userID, purpose)
+	return err
+}
+
+func (r *AccountTokenRepository) DeleteExpired() (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM account_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *AccountTokenRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
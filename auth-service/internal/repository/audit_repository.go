@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"auth-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(event *model.AuditEvent) error {
+	var details []byte
+	if event.Details != nil {
+		var err error
+		details, err = json.Marshal(event.Details)
+		if err != nil {
+			return err
+		}
+	}
+
+	var email, ip sql.NullString
+	if event.Email != "" {
+		email = sql.NullString{String: event.Email, Valid: true}
+	}
+	if event.IPAddress != "" {
+		ip = sql.NullString{String: event.IPAddress, Valid: true}
+	}
+
+	query := `
+		INSERT INTO auth_audit_log (id, event, user_id, actor_id, email, ip_address, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(query,
+		event.ID,
+		event.Event,
+		event.UserID,
+		event.ActorID,
+		email,
+		ip,
+		details,
+		event.CreatedAt,
+	)
+	return err
+}
+
+func (r *AuditRepository) FindAll(filter model.AuditFilter) ([]model.AuditEvent, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("(user_id = $%d OR actor_id = $%d)", len(args), len(args)))
+	}
+	if filter.Event != "" {
+		args = append(args, filter.Event)
+		conditions = append(conditions, fmt.Sprintf("event = $%d", len(args)))
+	}
+
+	query := `
+		SELECT id, event, user_id, actor_id, COALESCE(email, ''), COALESCE(ip_address, ''), details, created_at,
+			COUNT(*) OVER()
+		FROM auth_audit_log
+	`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	return scanAuditEvents(rows)
+}
+
+// FindByUserID returns every event the user was the subject or actor of,
+// for personal data exports.
+func (r *AuditRepository) FindByUserID(userID uuid.UUID) ([]model.AuditEvent, error) {
+	query := `
+		SELECT id, event, user_id, actor_id, COALESCE(email, ''), COALESCE(ip_address, ''), details, created_at,
+			COUNT(*) OVER()
+		FROM auth_audit_log
+		WHERE user_id = $1 OR actor_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events, _, err := scanAuditEvents(rows)
+	return events, err
+}
+
+// ForgetEmailInTransaction drops the email from a user's audit events ahead
+// of deleting the account; the events themselves are kept.
+func (r *AuditRepository) ForgetEmailInTransaction(tx *sql.Tx, userID uuid.UUID) error {
+	_, err := tx.Exec(`UPDATE auth_audit_log SET email = NULL WHERE user_id = $1`, userID)
+	return err
+}
+
+// scanAuditEvents expects the event columns followed by a COUNT(*) OVER()
+// total.
+func scanAuditEvents(rows *sql.Rows) ([]model.AuditEvent, int, error) {
+	events := []model.AuditEvent{}
+	total := 0
+	for rows.Next() {
+		var event model.AuditEvent
+		var details []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.Event,
+			&event.UserID,
+			&event.ActorID,
+			&event.Email,
+			&event.IPAddress,
+			&details,
+			&event.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &event.Details); err != nil {
+				return nil, 0, err
+			}
+		}
+		events = append(events, event)
+	}
+
+	return events, total, rows.Err()
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"auth-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvitationNotFound = errors.New("invitation not found")
+
+type InvitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+func (r *InvitationRepository) Create(inv *model.Invitation) error {
+	query := `
+		INSERT INTO invitations (id, code_hash, role, department, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(query,
+		inv.ID,
+		inv.CodeHash,
+		inv.Role,
+		inv.Department,
+		inv.CreatedBy,
+		inv.ExpiresAt,
+		inv.CreatedAt,
+	)
+	return err
+}
+
+// FindUnusedForUpdate locks an unused, unexpired invitation so it can be
+// redeemed exactly once inside the caller's transaction.
+func (r *InvitationRepository) FindUnusedForUpdate(tx *sql.Tx, codeHash string) (*model.Invitation, error) {
+	query := `
+		SELECT id, code_hash, role, department, created_by, expires_at, created_at
+		FROM invitations
+		WHERE code_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE
+	`
+	inv := &model.Invitation{}
+	var dept sql.NullString
+
+	err := tx.QueryRow(query, codeHash).Scan(
+		&inv.ID,
+		&inv.CodeHash,
+		&inv.Role,
+		&dept,
+		&inv.CreatedBy,
+		&inv.ExpiresAt,
+		&inv.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	if dept.Valid {
+		inv.Department = &dept.String
+	}
+
+	return inv, nil
+}
+
+func (r *InvitationRepository) MarkUsedInTransaction(tx *sql.Tx, id, userID uuid.UUID) error {
+	query := `UPDATE invitations SET used_at = NOW(), used_by = $2 WHERE id = $1`
+	_, err := tx.Exec(query, id, userID)
+	return err
+}
+
+func (r *InvitationRepository) FindByCreator(createdBy uuid.UUID) ([]model.Invitation, error) {
+	query := `
+		SELECT id, role, department, created_by, expires_at, used_at, used_by, created_at
+		FROM invitations
+		WHERE created_by = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []model.Invitation
+	for rows.Next() {
+		var inv model.Invitation
+		var dept sql.NullString
+		var usedAt sql.NullTime
+		var usedBy sql.NullString
+
+		err := rows.Scan(
+			&inv.ID,
+			&inv.Role,
+			&dept,
+			&inv.CreatedBy,
+			&inv.ExpiresAt,
+			&usedAt,
+			&usedBy,
+			&inv.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if dept.Valid {
+			inv.Department = &dept.String
+		}
+		if usedAt.Valid {
+			inv.UsedAt = &usedAt.Time
+		}
+		if usedBy.Valid {
+			uid, _ := uuid.Parse(usedBy.String)
+			inv.UsedBy = &uid
+		}
+
+		invitations = append(invitations, inv)
+	}
+
+	return invitations, nil
+}
+
+func (r *InvitationRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
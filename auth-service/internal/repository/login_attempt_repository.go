@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) Record(email, ip string, success bool) error {
+	query := `INSERT INTO login_attempts (email, ip_address, success) VALUES ($1, $2, $3)`
+	_, err := r.db.Exec(query, strings.ToLower(email), ip, success)
+	return err
+}
+
+// RecentEmailFailures counts failures for an email since the later of the
+// window start and its last successful login, and returns when the most
+// recent failure happened.
+func (r *LoginAttemptRepository) RecentEmailFailures(email string, since time.Time) (int, *time.Time, error) {
+	query := `
+		SELECT COUNT(*), MAX(created_at)
+		FROM login_attempts
+		WHERE email = $1 AND success = FALSE AND created_at > GREATEST($2, (
+			SELECT COALESCE(MAX(created_at), $2) FROM login_attempts
+			WHERE email = $1 AND success = TRUE
+		))
+	`
+	var count int
+	var last sql.NullTime
+	if err := r.db.QueryRow(query, strings.ToLower(email), since).Scan(&count, &last); err != nil {
+		return 0, nil, err
+	}
+	if !last.Valid {
+		return count, nil, nil
+	}
+	return count, &last.Time, nil
+}
+
+func (r *LoginAttemptRepository) RecentIPFailures(ip string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM login_attempts WHERE ip_address = $1 AND success = FALSE AND created_at > $2`
+	var count int
+	err := r.db.QueryRow(query, ip, since).Scan(&count)
+	return count, err
+}
+
+func (r *LoginAttemptRepository) DeleteOlderThan(age time.Duration) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM login_attempts WHERE created_at < $1`, time.Now().Add(-age))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
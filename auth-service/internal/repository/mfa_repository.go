@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"auth-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var ErrMFANotFound = errors.New("mfa not found")
+
+type MFARepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+func (r *MFARepository) FindByUserID(userID uuid.UUID) (*model.UserMFA, error) {
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at
+		FROM user_mfa WHERE user_id = $1
+	`
+	mfa := &model.UserMFA{}
+	var enabledAt sql.NullTime
+
+	err := r.db.QueryRow(query, userID).Scan(
+		&mfa.UserID,
+		&mfa.Secret,
+		&enabledAt,
+		&mfa.LastUsedStep,
+		&mfa.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrMFANotFound
+		}
+		return nil, err
+	}
+
+	if enabledAt.Valid {
+		mfa.EnabledAt = &enabledAt.Time
+	}
+
+	return mfa, nil
+}
+
+// SavePending stores a fresh secret for enrolment. An already enabled
+// secret is never overwritten; false is returned in that case.
+func (r *MFARepository) SavePending(userID uuid.UUID, secret string) (bool, error) {
+	query := `
+		INSERT INTO user_mfa (user_id, secret, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE user_mfa.enabled_at IS NULL
+	`
+	result, err := r.db.Exec(query, userID, secret)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *MFARepository) EnableInTransaction(tx *sql.Tx, userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE user_mfa SET enabled_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NULL
+	`
+	result, err := tx.Exec(query, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// UseStep records a TOTP time step as spent. It reports false when the step
+// (or a later one) was already used, which blocks code replay.
+func (r *MFARepository) UseStep(userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE user_mfa SET last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+	`
+	result, err := r.db.Exec(query, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *MFARepository) Delete(userID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM user_mfa WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *MFARepository) ReplaceRecoveryCodesInTransaction(tx *sql.Tx, userID uuid.UUID, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		_, err := tx.Exec(`
+			INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, NOW())
+		`, uuid.New(), userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *MFARepository) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	result, err := r.db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *MFARepository) CountUnusedRecoveryCodes(userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+	var count int
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *MFARepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
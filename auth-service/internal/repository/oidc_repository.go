@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"auth-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStateNotFound    = errors.New("state not found")
+	ErrIdentityNotFound = errors.New("identity not found")
+)
+
+type OIDCRepository struct {
+	db *sql.DB
+}
+
+func NewOIDCRepository(db *sql.DB) *OIDCRepository {
+	return &OIDCRepository{db: db}
+}
+
+func (r *OIDCRepository) CreateState(state *model.OIDCLoginState) error {
+	query := `
+		INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.Exec(query, state.StateHash, state.Nonce, state.CodeVerifier, state.ExpiresAt)
+	return err
+}
+
+// ConsumeState deletes and returns a pending state, so a callback can only
+// be processed once.
+func (r *OIDCRepository) ConsumeState(stateHash string) (*model.OIDCLoginState, error) {
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1 AND expires_at > NOW()
+		RETURNING state_hash, nonce, code_verifier, expires_at
+	`
+	state := &model.OIDCLoginState{}
+	err := r.db.QueryRow(query, stateHash).Scan(
+		&state.StateHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStateNotFound
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+func (r *OIDCRepository) DeleteExpiredStates() (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM oidc_login_states WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *OIDCRepository) FindIdentity(issuer, subject string) (*model.UserIdentity, error) {
+	query := `
+		SELECT issuer, subject, user_id, COALESCE(email, ''), created_at, last_login_at
+		FROM user_identities
+		WHERE issuer = $1 AND subject = $2
+	`
+	identity := &model.UserIdentity{}
+	err := r.db.QueryRow(query, issuer, subject).Scan(
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (r *OIDCRepository) FindIdentitiesByUserID(userID uuid.UUID) ([]model.UserIdentity, error) {
+	query := `
+		SELECT issuer, subject, user_id, COALESCE(email, ''), created_at, last_login_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []model.UserIdentity{}
+	for rows.Next() {
+		var identity model.UserIdentity
+		err := rows.Scan(
+			&identity.Issuer,
+			&identity.Subject,
+			&identity.UserID,
+			&identity.Email,
+			&identity.CreatedAt,
+			&identity.LastLoginAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+func (r *OIDCRepository) CreateIdentityInTransaction(tx *sql.Tx, identity *model.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (issuer, subject, user_id, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.Exec(query,
+		identity.Issuer,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+		identity.CreatedAt,
+		identity.LastLoginAt,
+	)
+	return err
+}
+
+func (r *OIDCRepository) TouchIdentity(issuer, subject, email string) error {
+	query := `UPDATE user_identities SET last_login_at = NOW(), email = $3 WHERE issuer = $1 AND subject = $2`
+	_, err := r.db.Exec(query, issuer, subject, email)
+	return err
+}
+
+func (r *OIDCRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+
+	"auth-service/internal/model"
+)
+
+type RoleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// FindAll returns every role with its permissions attached.
+func (r *RoleRepository) FindAll() ([]model.RoleDefinition, error) {
+	rows, err := r.db.Query(`
+		SELECT r.name, COALESCE(r.description, ''), r.department, r.is_admin, rp.permission
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		ORDER BY r.name, rp.permission
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []model.RoleDefinition
+	for rows.Next() {
+		var role model.RoleDefinition
+		var dept, permission sql.NullString
+		if err := rows.Scan(&role.Name, &role.Description, &dept, &role.IsAdmin, &permission); err != nil {
+			return nil, err
+		}
+
+		if n := len(roles); n == 0 || roles[n-1].Name != role.Name {
+			if dept.Valid {
+				role.Department = &dept.String
+			}
+			role.Permissions = []string{}
+			roles = append(roles, role)
+		}
+		if permission.Valid {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, permission.String)
+		}
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *RoleRepository) PermissionExists(name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM permissions WHERE name = $1)`, name).Scan(&exists)
+	return exists, err
+}
+
+func (r *RoleRepository) DepartmentExists(code string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM departments WHERE code = $1)`, code).Scan(&exists)
+	return exists, err
+}
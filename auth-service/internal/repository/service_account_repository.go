@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"auth-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+)
+
+type ServiceAccountRepository struct {
+	db *sql.DB
+}
+
+func NewServiceAccountRepository(db *sql.DB) *ServiceAccountRepository {
+	return &ServiceAccountRepository{db: db}
+}
+
+func (r *ServiceAccountRepository) Create(account *model.ServiceAccount) error {
+	query := `
+		INSERT INTO service_accounts (id, name, description, department, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query,
+		account.ID,
+		account.Name,
+		account.Description,
+		account.Department,
+		account.CreatedBy,
+		account.CreatedAt,
+	)
+	return err
+}
+
+func (r *ServiceAccountRepository) NameExists(name string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM service_accounts WHERE name = $1)`
+	var exists bool
+	err := r.db.QueryRow(query, name).Scan(&exists)
+	return exists, err
+}
+
+const serviceAccountColumns = `id, name, COALESCE(description, ''), department, created_by, created_at`
+
+func (r *ServiceAccountRepository) FindByID(id uuid.UUID) (*model.ServiceAccount, error) {
+	query := `SELECT ` + serviceAccountColumns + ` FROM service_accounts WHERE id = $1`
+	account, err := scanServiceAccount(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrServiceAccountNotFound
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+func (r *ServiceAccountRepository) FindAll() ([]model.ServiceAccount, error) {
+	rows, err := r.db.Query(`SELECT ` + serviceAccountColumns + ` FROM service_accounts ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []model.ServiceAccount{}
+	for rows.Next() {
+		account, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, rows.Err()
+}
+
+func scanServiceAccount(row rowScanner) (*model.ServiceAccount, error) {
+	account := &model.ServiceAccount{Keys: []model.APIKey{}}
+	var dept sql.NullString
+	var createdBy uuid.NullUUID
+
+	if err := row.Scan(
+		&account.ID,
+		&account.Name,
+		&account.Description,
+		&dept,
+		&createdBy,
+		&account.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if dept.Valid {
+		account.Department = &dept.String
+	}
+	if createdBy.Valid {
+		account.CreatedBy = &createdBy.UUID
+	}
+	return account, nil
+}
+
+// CreateKeyInTransaction stores a key together with its scopes.
+func (r *ServiceAccountRepository) CreateKeyInTransaction(tx *sql.Tx, key *model.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, service_account_id, name, prefix, key_hash, expires_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := tx.Exec(query,
+		key.ID,
+		key.ServiceAccountID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.ExpiresAt,
+		key.CreatedBy,
+		key.CreatedAt,
+	); err != nil {
+		return err
+	}
+
+	for _, scope := range key.Scopes {
+		if _, err := tx.Exec(`INSERT INTO api_key_scopes (api_key_id, permission) VALUES ($1, $2)`, key.ID, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const apiKeyColumns = `k.id, k.service_account_id, k.name, k.prefix, k.key_hash, k.expires_at, k.last_used_at,
+	COALESCE(k.last_used_ip, ''), k.created_by, k.created_at, k.revoked_at, s.permission`
+
+// FindKeys returns the keys of one service account, or of every account
+// when accountID is nil, with their scopes attached.
+func (r *ServiceAccountRepository) FindKeys(accountID *uuid.UUID) ([]model.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys k
+		LEFT JOIN api_key_scopes s ON s.api_key_id = k.id
+		WHERE $1::uuid IS NULL OR k.service_account_id = $1
+		ORDER BY k.created_at DESC, k.id, s.permission
+	`
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAPIKeys(rows)
+}
+
+// FindKeyByHash looks up a key by the hash of its plaintext. Revoked and
+// expired keys are returned as well; the caller decides what to accept.
+func (r *ServiceAccountRepository) FindKeyByHash(keyHash string) (*model.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys k
+		LEFT JOIN api_key_scopes s ON s.api_key_id = k.id
+		WHERE k.key_hash = $1
+		ORDER BY s.permission
+	`
+	rows, err := r.db.Query(query, keyHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys, err := scanAPIKeys(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &keys[0], nil
+}
+
+func scanAPIKeys(rows *sql.Rows) ([]model.APIKey, error) {
+	keys := []model.APIKey{}
+	for rows.Next() {
+		var key model.APIKey
+		var expiresAt, lastUsedAt, revokedAt sql.NullTime
+		var createdBy uuid.NullUUID
+		var scope sql.NullString
+
+		if err := rows.Scan(
+			&key.ID,
+			&key.ServiceAccountID,
+			&key.Name,
+			&key.Prefix,
+			&key.KeyHash,
+			&expiresAt,
+			&lastUsedAt,
+			&key.LastUsedIP,
+			&createdBy,
+			&key.CreatedAt,
+			&revokedAt,
+			&scope,
+		); err != nil {
+			return nil, err
+		}
+
+		if n := len(keys); n == 0 || keys[n-1].ID != key.ID {
+			if expiresAt.Valid {
+				key.ExpiresAt = &expiresAt.Time
+			}
+			if lastUsedAt.Valid {
+				key.LastUsedAt = &lastUsedAt.Time
+			}
+			if revokedAt.Valid {
+				key.RevokedAt = &revokedAt.Time
+			}
+			if createdBy.Valid {
+				key.CreatedBy = &createdBy.UUID
+			}
+			key.Scopes = []string{}
+			keys = append(keys, key)
+		}
+		if scope.Valid {
+			last := &keys[len(keys)-1]
+			last.Scopes = append(last.Scopes, scope.String)
+		}
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *ServiceAccountRepository) RevokeKey(accountID, keyID uuid.UUID) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND service_account_id = $2 AND revoked_at IS NULL`
+	result, err := r.db.Exec(query, keyID, accountID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// TouchKey records the key's last use. Writes are coalesced to one per
+// interval so a busy integration doesn't update the row on every request.
+func (r *ServiceAccountRepository) TouchKey(id uuid.UUID, ip string, interval time.Duration) error {
+	query := `
+		UPDATE api_keys SET last_used_at = NOW(), last_used_ip = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3 OR last_used_ip IS DISTINCT FROM $2)
+	`
+	_, err := r.db.Exec(query, id, ip, time.Now().Add(-interval))
+	return err
+}
+
+func (r *ServiceAccountRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"auth-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrSessionNotFound      = errors.New("session not found")
+)
+
+type TokenRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRepository(db *sql.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) CreateRefreshToken(token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+func (r *TokenRepository) FindRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1
+	`
+	token := &model.RefreshToken{}
+	var revokedAt sql.NullTime
+
+	err := r.db.QueryRow(query, hash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return token, nil
+}
+
+// RevokeRefreshToken marks a single token as used. It reports false when the
+// token was already revoked, which lets callers detect concurrent reuse.
+func (r *TokenRepository) RevokeRefreshToken(id uuid.UUID) (bool, error) {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// RevokeRefreshTokenFamily ends the session the family belongs to, which
+// also invalidates the session's outstanding access tokens.
+func (r *TokenRepository) RevokeRefreshTokenFamily(familyID uuid.UUID) error {
+	query := `
+		WITH ended AS (
+			UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL
+	`
+	_, err := r.db.Exec(query, familyID)
+	return err
+}
+
+// RevokeAllRefreshTokens signs the user out everywhere.
+func (r *TokenRepository) RevokeAllRefreshTokens(userID uuid.UUID) error {
+	query := `
+		WITH ended AS (
+			UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL
+	`
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+func (r *TokenRepository) CreateSession(session *model.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastSeenAt,
+	)
+	return err
+}
+
+const sessionColumns = `id, user_id, COALESCE(user_agent, ''), COALESCE(ip_address, ''), created_at, last_seen_at, revoked_at`
+
+func (r *TokenRepository) FindSession(id uuid.UUID) (*model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+	session, err := scanSession(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// FindActiveSessions lists sessions that are neither revoked nor past the
+// lifetime of their last refresh token.
+func (r *TokenRepository) FindActiveSessions(userID uuid.UUID) ([]model.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions s
+		WHERE user_id = $1 AND revoked_at IS NULL
+			AND EXISTS (
+				SELECT 1 FROM refresh_tokens rt
+				WHERE rt.family_id = s.id AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+			)
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []model.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+func scanSession(row rowScanner) (*model.Session, error) {
+	session := &model.Session{}
+	var revokedAt sql.NullTime
+
+	if err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&revokedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return session, nil
+}
+
+// TouchSession moves last_seen_at forward, at most once per interval unless
+// the client IP changed.
+func (r *TokenRepository) TouchSession(id uuid.UUID, ip string, interval time.Duration) error {
+	query := `
+		UPDATE sessions SET last_seen_at = NOW(), ip_address = COALESCE(NULLIF($2, ''), ip_address)
+		WHERE id = $1 AND (last_seen_at < $3 OR ($2 <> '' AND ip_address IS DISTINCT FROM $2))
+	`
+	_, err := r.db.Exec(query, id, ip, time.Now().Add(-interval))
+	return err
+}
+
+func (r *TokenRepository) RevokeAccessToken(jti, userID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := r.db.Exec(query, jti, userID, expiresAt)
+	return err
+}
+
+func (r *TokenRepository) IsAccessTokenRevoked(jti uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`
+	var exists bool
+	err := r.db.QueryRow(query, jti).Scan(&exists)
+	return exists, err
+}
+
+func (r *TokenRepository) DeleteExpired() (int64, error) {
+	var total int64
+
+	result, err := r.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	total += n
+
+	result, err = r.db.Exec(`DELETE FROM refresh_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return total, err
+	}
+	n, _ = result.RowsAffected()
+	total += n
+
+	// Sessions whose refresh tokens have all been purged are over
+	result, err = r.db.Exec(`
+		DELETE FROM sessions s
+		WHERE created_at < NOW() - INTERVAL '1 hour'
+			AND NOT EXISTS (SELECT 1 FROM refresh_tokens rt WHERE rt.family_id = s.id)
+	`)
+	if err != nil {
+		return total, err
+	}
+	n, _ = result.RowsAffected()
+	total += n
+
+	return total, nil
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"auth-service/internal/model"
 
@@ -10,6 +13,11 @@ import (
 	_ "github.com/lib/pq"
 )
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailInUse   = errors.New("email already registered")
+)
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -35,26 +43,62 @@ func (r *UserRepository) Create(user *model.User) error {
 	return err
 }
 
-func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
+func (r *UserRepository) CreateInTransaction(tx *sql.Tx, user *model.User) error {
 	query := `
-		SELECT id, email, password_hash, name, role, department, created_at
-		FROM users WHERE email = $1
+		INSERT INTO users (id, email, password_hash, name, role, department, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
+	_, err := tx.Exec(query,
+		user.ID,
+		user.Email,
+		user.PasswordHash,
+		user.Name,
+		user.Role,
+		user.Department,
+		user.CreatedAt,
+	)
+	return err
+}
+
+const userColumns = `id, email, password_hash, name, role, department, email_verified_at, pending_email, locked_until, is_active, deactivated_at, created_at`
+
+func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return scanUser(r.db.QueryRow(query, email))
+}
+
+func (r *UserRepository) FindByID(id uuid.UUID) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(r.db.QueryRow(query, id))
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner, extra ...interface{}) (*model.User, error) {
 	user := &model.User{}
-	var dept sql.NullString
+	var dept, pendingEmail sql.NullString
+	var verifiedAt, lockedUntil, deactivatedAt sql.NullTime
 
-	err := r.db.QueryRow(query, email).Scan(
+	dest := []interface{}{
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
 		&user.Role,
 		&dept,
+		&verifiedAt,
+		&pendingEmail,
+		&lockedUntil,
+		&user.IsActive,
+		&deactivatedAt,
 		&user.CreatedAt,
-	)
+	}
+	err := row.Scan(append(dest, extra...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -62,39 +106,257 @@ func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
 	if dept.Valid {
 		user.Department = &dept.String
 	}
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
+	if pendingEmail.Valid {
+		user.PendingEmail = &pendingEmail.String
+	}
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.Time
+	}
+	if deactivatedAt.Valid {
+		user.DeactivatedAt = &deactivatedAt.Time
+	}
 
 	return user, nil
 }
 
-func (r *UserRepository) FindByID(id uuid.UUID) (*model.User, error) {
+// FindAll returns one page of users matching the filter together with the
+// total number of matches.
+func (r *UserRepository) FindAll(filter model.UserFilter) ([]model.User, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+strings.ToLower(filter.Search)+"%")
+		conditions = append(conditions, fmt.Sprintf("(LOWER(email) LIKE $%d OR LOWER(name) LIKE $%d)", len(args), len(args)))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.Department != "" {
+		args = append(args, filter.Department)
+		conditions = append(conditions, fmt.Sprintf("department = $%d", len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+
+	query := `SELECT ` + userColumns + `, COUNT(*) OVER() FROM users`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	total := 0
+	for rows.Next() {
+		user, err := scanUser(rows, &total)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, total, rows.Err()
+}
+
+func (r *UserRepository) UpdateRole(id uuid.UUID, role model.Role, department *string) error {
+	query := `UPDATE users SET role = $1, department = $2 WHERE id = $3`
+	result, err := r.db.Exec(query, role, department, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) SetActive(id uuid.UUID, active bool) error {
 	query := `
-		SELECT id, email, password_hash, name, role, department, created_at
-		FROM users WHERE id = $1
+		UPDATE users SET
+			is_active = $1,
+			deactivated_at = CASE WHEN $1 THEN NULL ELSE NOW() END
+		WHERE id = $2
 	`
-	user := &model.User{}
-	var dept sql.NullString
+	result, err := r.db.Exec(query, active, id)
+	if err != nil {
+		return err
+	}
 
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Name,
-		&user.Role,
-		&dept,
-		&user.CreatedAt,
-	)
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(id uuid.UUID, passwordHash string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.UpdatePasswordInTransaction(tx, id, passwordHash); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *UserRepository) UpdatePasswordInTransaction(tx *sql.Tx, id uuid.UUID, passwordHash string) error {
+	// A successful reset also lifts any brute-force lockout
+	query := `UPDATE users SET password_hash = $1, failed_login_count = 0, locked_until = NULL WHERE id = $2`
+	result, err := tx.Exec(query, passwordHash, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateName(id uuid.UUID, name string) error {
+	query := `UPDATE users SET name = $1 WHERE id = $2`
+	result, err := r.db.Exec(query, name, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) SetPendingEmail(id uuid.UUID, email string) error {
+	query := `UPDATE users SET pending_email = $1 WHERE id = $2`
+	_, err := r.db.Exec(query, email, id)
+	return err
+}
+
+// ApplyPendingEmailInTransaction swaps in the confirmed pending email. It
+// fails if another account has claimed that address in the meantime.
+func (r *UserRepository) ApplyPendingEmailInTransaction(tx *sql.Tx, id uuid.UUID) (string, error) {
+	query := `
+		UPDATE users SET email = pending_email, pending_email = NULL, email_verified_at = NOW()
+		WHERE id = $1 AND pending_email IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM users other WHERE other.email = users.pending_email)
+		RETURNING email
+	`
+	var email string
+	if err := tx.QueryRow(query, id).Scan(&email); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return "", ErrEmailInUse
+		}
+		return "", err
+	}
+	return email, nil
+}
+
+func (r *UserRepository) MarkEmailVerifiedInTransaction(tx *sql.Tx, id uuid.UUID) error {
+	query := `UPDATE users SET email_verified_at = NOW() WHERE id = $1 AND email_verified_at IS NULL`
+	_, err := tx.Exec(query, id)
+	return err
+}
+
+// RecordFailedLogin bumps the consecutive failure counter. Once it reaches
+// maxFailures the account is locked for lockFor and the counter starts over.
+// The returned time is non-nil only when this call applied the lock.
+func (r *UserRepository) RecordFailedLogin(id uuid.UUID, maxFailures int, lockFor time.Duration) (*time.Time, error) {
+	query := `
+		UPDATE users SET
+			failed_login_count = CASE WHEN failed_login_count + 1 >= $2 THEN 0 ELSE failed_login_count + 1 END,
+			locked_until = CASE WHEN failed_login_count + 1 >= $2 THEN $3 ELSE locked_until END
+		WHERE id = $1
+		RETURNING failed_login_count = 0
+	`
+	lockedUntil := time.Now().Add(lockFor)
+	var locked bool
+	if err := r.db.QueryRow(query, id, maxFailures, lockedUntil).Scan(&locked); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
+	if !locked {
+		return nil, nil
+	}
+	return &lockedUntil, nil
+}
 
-	if dept.Valid {
-		user.Department = &dept.String
+func (r *UserRepository) ResetFailedLogins(id uuid.UUID) error {
+	query := `UPDATE users SET failed_login_count = 0, locked_until = NULL WHERE id = $1 AND (failed_login_count > 0 OR locked_until IS NOT NULL)`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *UserRepository) Unlock(id uuid.UUID) error {
+	query := `UPDATE users SET failed_login_count = 0, locked_until = NULL WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
 	}
 
-	return user, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteInTransaction removes the account row; sessions, tokens, MFA,
+// identities, votes and notifications go with it through ON DELETE CASCADE.
+func (r *UserRepository) DeleteInTransaction(tx *sql.Tx, id uuid.UUID) error {
+	result, err := tx.Exec(`DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
 }
 
 func (r *UserRepository) EmailExists(email string) (bool, error) {
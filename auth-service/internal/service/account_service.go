@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"auth-service/internal/mailer"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordResetTTL     = 1 * time.Hour
+	emailVerificationTTL = 48 * time.Hour
+)
+
+type AccountService struct {
+	userRepo  *repository.UserRepository
+	tokenRepo *repository.TokenRepository
+	acctRepo  *repository.AccountTokenRepository
+	auditRepo *repository.AuditRepository
+	mailer    mailer.Mailer
+	appURL    string
+}
+
+func NewAccountService(userRepo *repository.UserRepository, tokenRepo *repository.TokenRepository, acctRepo *repository.AccountTokenRepository, auditRepo *repository.AuditRepository, m mailer.Mailer, appURL string) *AccountService {
+	return &AccountService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		acctRepo:  acctRepo,
+		auditRepo: auditRepo,
+		mailer:    m,
+		appURL:    strings.TrimRight(appURL, "/"),
+	}
+}
+
+// ForgotPassword never reports whether the email exists, so the endpoint
+// can't be used to enumerate accounts.
+func (s *AccountService) ForgotPassword(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	return s.sendPasswordReset(user,
+		"Kami menerima permintaan reset password untuk akun Anda.",
+		"Abaikan email ini jika Anda tidak meminta reset password.")
+}
+
+// ForcePasswordReset emails a reset link after an administrator has
+// invalidated the user's password.
+func (s *AccountService) ForcePasswordReset(user *model.User) error {
+	return s.sendPasswordReset(user,
+		"Administrator CityConnect meminta Anda membuat password baru. Password lama Anda sudah tidak berlaku.",
+		"Hubungi administrator jika Anda tidak mengenali permintaan ini.")
+}
+
+func (s *AccountService) sendPasswordReset(user *model.User, intro, footer string) error {
+	token, err := s.issueToken(user.ID, model.PurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	s.sendAsync(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password akun CityConnect",
+		Body: fmt.Sprintf("Halo %s,\n\n%s\n"+
+			"Buka tautan berikut dalam %d menit untuk membuat password baru:\n\n%s/reset-password?token=%s\n\n"+
+			"%s\n",
+			user.Name, intro, int(passwordResetTTL.Minutes()), s.appURL, token, footer),
+	})
+
+	return nil
+}
+
+func (s *AccountService) ResetPassword(req *model.ResetPasswordRequest) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.acctRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	token, err := s.acctRepo.ConsumeInTransaction(tx, hashToken(req.Token), model.PurposePasswordReset)
+	if err != nil {
+		return errors.New("invalid or expired token")
+	}
+
+	if err := s.userRepo.UpdatePasswordInTransaction(tx, token.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	// Whoever received the reset link also owns the mailbox, so a reset
+	// counts as verifying it
+	if err := s.userRepo.MarkEmailVerifiedInTransaction(tx, token.UserID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Sign out every existing session after a password change
+	if err := s.tokenRepo.RevokeAllRefreshTokens(token.UserID); err != nil {
+		log.Printf("reset password: revoke sessions for %s: %v", token.UserID, err)
+	}
+
+	return nil
+}
+
+func (s *AccountService) SendEmailVerification(user *model.User) error {
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, err := s.issueToken(user.ID, model.PurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	s.sendAsync(mailer.Message{
+		To:      user.Email,
+		Subject: "Verifikasi email CityConnect",
+		Body: fmt.Sprintf("Halo %s,\n\nTerima kasih telah mendaftar di CityConnect.\n"+
+			"Buka tautan berikut untuk memverifikasi alamat email Anda:\n\n%s/verify-email?token=%s\n",
+			user.Name, s.appURL, token),
+	})
+
+	return nil
+}
+
+// RequestEmailChange parks the new address on the account and mails a
+// confirmation link to it. The login email stays the same until the link is
+// opened; the old address is told about the request.
+func (s *AccountService) RequestEmailChange(user *model.User, newEmail string) error {
+	if err := s.acctRepo.DeleteUnused(user.ID, model.PurposeEmailChange); err != nil {
+		return err
+	}
+	if err := s.userRepo.SetPendingEmail(user.ID, newEmail); err != nil {
+		return err
+	}
+
+	token, err := s.issueToken(user.ID, model.PurposeEmailChange, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	s.sendAsync(mailer.Message{
+		To:      newEmail,
+		Subject: "Konfirmasi perubahan email CityConnect",
+		Body: fmt.Sprintf("Halo %s,\n\nAnda meminta untuk mengganti email akun CityConnect ke alamat ini.\n"+
+			"Buka tautan berikut untuk mengonfirmasi:\n\n%s/verify-email?token=%s\n",
+			user.Name, s.appURL, token),
+	})
+	s.sendAsync(mailer.Message{
+		To:      user.Email,
+		Subject: "Permintaan perubahan email CityConnect",
+		Body: fmt.Sprintf("Halo %s,\n\nAda permintaan untuk mengganti email akun Anda menjadi %s.\n"+
+			"Email lama tetap berlaku sampai perubahan dikonfirmasi. Segera ganti password Anda jika Anda tidak merasa memintanya.\n",
+			user.Name, newEmail),
+	})
+
+	return nil
+}
+
+// VerifyEmail accepts both registration links and email change links, which
+// share the /verify-email page.
+func (s *AccountService) VerifyEmail(rawToken string) error {
+	tx, err := s.acctRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	hash := hashToken(rawToken)
+	token, err := s.acctRepo.ConsumeInTransaction(tx, hash, model.PurposeEmailVerification)
+	if err == nil {
+		if err := s.userRepo.MarkEmailVerifiedInTransaction(tx, token.UserID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	token, err = s.acctRepo.ConsumeInTransaction(tx, hash, model.PurposeEmailChange)
+	if err != nil {
+		return errors.New("invalid or expired token")
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return err
+	}
+	newEmail, err := s.userRepo.ApplyPendingEmailInTransaction(tx, token.UserID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:  model.AuditEmailChanged,
+		UserID: &user.ID,
+		Email:  newEmail,
+		Details: map[string]interface{}{
+			"old_email": user.Email,
+		},
+	})
+	return nil
+}
+
+func (s *AccountService) PurgeExpiredTokens() (int64, error) {
+	return s.acctRepo.DeleteExpired()
+}
+
+func (s *AccountService) issueToken(userID uuid.UUID, purpose model.TokenPurpose, ttl time.Duration) (string, error) {
+	raw, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := &model.AccountToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := s.acctRepo.Create(token); err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+func (s *AccountService) sendAsync(msg mailer.Message) {
+	go func() {
+		if err := s.mailer.Send(msg); err != nil {
+			log.Printf("mail to %s failed: %v", msg.To, err)
+		}
+	}()
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// writeAudit records an auth audit event. Failures are logged rather than
+// returned so auditing never blocks the action being audited.
+func writeAudit(repo *repository.AuditRepository, event *model.AuditEvent) {
+	event.ID = uuid.New()
+	event.CreatedAt = time.Now()
+	if err := repo.Create(event); err != nil {
+		log.Printf("audit %s: %v", event.Event, err)
+	}
+}
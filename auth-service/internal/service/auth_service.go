@@ -1,7 +1,11 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
 
 	"auth-service/config"
@@ -13,15 +17,48 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	defaultAccessTokenMinutes = 15
+	defaultRefreshTokenHours  = 24 * 7
+	sessionTouchInterval      = time.Minute
+	maxUserAgentLength        = 512
+)
+
+var (
+	ErrAccountDeactivated = errors.New("account has been deactivated")
+	ErrUserNotFound       = repository.ErrUserNotFound
+	ErrSessionNotFound    = repository.ErrSessionNotFound
+	ErrEmailInUse         = repository.ErrEmailInUse
+)
+
 type AuthService struct {
-	userRepo  *repository.UserRepository
-	jwtConfig config.JWTConfig
+	userRepo   *repository.UserRepository
+	tokenRepo  *repository.TokenRepository
+	inviteRepo *repository.InvitationRepository
+	roles      *RoleService
+	keyManager *KeyManager
+	loginGuard *LoginGuard
+	mfaService *MFAService
+	jwtConfig  config.JWTConfig
 }
 
-func NewAuthService(userRepo *repository.UserRepository, jwtConfig config.JWTConfig) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, tokenRepo *repository.TokenRepository, inviteRepo *repository.InvitationRepository, roles *RoleService, keyManager *KeyManager, loginGuard *LoginGuard, mfaService *MFAService, jwtConfig config.JWTConfig) *AuthService {
+	if jwtConfig.AccessTokenMinutes <= 0 {
+		jwtConfig.AccessTokenMinutes = defaultAccessTokenMinutes
+	}
+	if jwtConfig.RefreshTokenHours <= 0 {
+		jwtConfig.RefreshTokenHours = defaultRefreshTokenHours
+	}
+
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtConfig: jwtConfig,
+		userRepo:   userRepo,
+		tokenRepo:  tokenRepo,
+		inviteRepo: inviteRepo,
+		roles:      roles,
+		keyManager: keyManager,
+		loginGuard: loginGuard,
+		mfaService: mfaService,
+		jwtConfig:  jwtConfig,
 	}
 }
 
@@ -32,7 +69,7 @@ func (s *AuthService) Register(req *model.RegisterRequest) (*model.User, error)
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("email already registered")
+		return nil, ErrEmailInUse
 	}
 
 	// Hash password
@@ -41,113 +78,599 @@ func (s *AuthService) Register(req *model.RegisterRequest) (*model.User, error)
 		return nil, err
 	}
 
-	var department *string
-	switch req.Role {
-	case model.RoleAdminKebersihan:
-		dept := "kebersihan"
-		department = &dept
-	case model.RoleAdminKesehatan:
-		dept := "kesehatan"
-		department = &dept
-	case model.RoleAdminInfrastruktur:
-		dept := "infrastruktur"
-		department = &dept
-	}
-
+	// Self-registration always yields a citizen account; admin roles are
+	// only granted through an invitation.
 	user := &model.User{
 		ID:           uuid.New(),
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword),
 		Name:         req.Name,
-		Role:         req.Role,
-		Department:   department,
+		Role:         model.DefaultRole,
 		CreatedAt:    time.Now(),
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if req.InviteCode == "" {
+		if err := s.userRepo.Create(user); err != nil {
+			return nil, err
+		}
+		return s.withPermissions(user)
+	}
+
+	tx, err := s.inviteRepo.BeginTx()
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	return user, nil
+	invitation, err := s.inviteRepo.FindUnusedForUpdate(tx, hashToken(strings.TrimSpace(req.InviteCode)))
+	if err != nil {
+		return nil, errors.New("invalid or expired invite code")
+	}
+
+	role, err := s.roles.Get(invitation.Role)
+	if err != nil {
+		return nil, errors.New("invalid or expired invite code")
+	}
+
+	user.Role = role.Name
+	user.Department = invitation.Department
+	if user.Department == nil {
+		user.Department = role.Department
+	}
+
+	if err := s.userRepo.CreateInTransaction(tx, user); err != nil {
+		return nil, err
+	}
+	if err := s.inviteRepo.MarkUsedInTransaction(tx, invitation.ID, user.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.withPermissions(user)
 }
 
-func (s *AuthService) Login(req *model.LoginRequest) (*model.LoginResponse, error) {
+func (s *AuthService) Login(req *model.LoginRequest, client model.ClientInfo) (*model.LoginResponse, error) {
+	clientIP := client.IP
+	if err := s.loginGuard.Check(req.Email, clientIP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
+		s.loginGuard.RecordFailure(nil, req.Email, clientIP)
 		return nil, errors.New("invalid email or password")
 	}
 
+	if err := s.loginGuard.CheckLocked(user); err != nil {
+		return nil, err
+	}
+
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		s.loginGuard.RecordFailure(user, req.Email, clientIP)
 		return nil, errors.New("invalid email or password")
 	}
 
-	// Generate JWT
-	token, err := s.generateToken(user)
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	// With MFA the password only earns a challenge; tokens are issued by
+	// CompleteMFALogin once the second factor checks out.
+	mfaEnabled, err := s.mfaService.IsEnabled(user.ID)
 	if err != nil {
 		return nil, err
 	}
+	mfaRequired, err := s.mfaService.RequiredFor(user)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled || mfaRequired {
+		challenge, err := s.mfaService.IssueChallenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.LoginResponse{
+			MFARequired:           true,
+			MFAEnrollmentRequired: !mfaEnabled,
+			ChallengeToken:        challenge,
+		}, nil
+	}
 
-	return &model.LoginResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	s.loginGuard.RecordSuccess(user, clientIP)
+
+	return s.startSession(user, client)
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*model.ValidateResponse, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
+// CompleteMFALogin finishes a login started with a challenge. When policy
+// forced enrolment during login, the code activates the pending secret and
+// the recovery codes are returned alongside the tokens.
+func (s *AuthService) CompleteMFALogin(req *model.MFALoginRequest, client model.ClientInfo) (*model.LoginResponse, error) {
+	clientIP := client.IP
+	user, err := s.mfaService.ChallengeUser(req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	if err := s.loginGuard.Check(user.Email, clientIP); err != nil {
+		return nil, err
+	}
+	if err := s.loginGuard.CheckLocked(user); err != nil {
+		return nil, err
+	}
+
+	mfaEnabled, err := s.mfaService.IsEnabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if mfaEnabled {
+		err = s.mfaService.Verify(user.ID, req.Code)
+	} else {
+		recoveryCodes, err = s.mfaService.Activate(user.ID, req.Code)
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			s.loginGuard.RecordFailure(user, user.Email, clientIP)
 		}
-		return []byte(s.jwtConfig.Secret), nil
-	})
+		return nil, err
+	}
 
-	if err != nil || !token.Valid {
-		return &model.ValidateResponse{Valid: false}, nil
+	if err := s.mfaService.ConsumeChallenge(req.ChallengeToken); err != nil {
+		return nil, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
+	s.loginGuard.RecordSuccess(user, clientIP)
+
+	response, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	response.RecoveryCodes = recoveryCodes
+	return response, nil
+}
+
+// EnrollDuringLogin lets a user who is forced into MFA by policy fetch a
+// secret using their challenge token, before they hold an access token.
+func (s *AuthService) EnrollDuringLogin(challengeToken string) (*model.MFAEnrollResponse, error) {
+	user, err := s.mfaService.ChallengeUser(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.mfaService.Enroll(user.ID)
+}
+
+func (s *AuthService) Refresh(refreshToken, clientIP string) (*model.LoginResponse, error) {
+	stored, err := s.tokenRepo.FindRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// A rotated token being presented again means it was copied somewhere;
+	// kill the whole family so neither party can keep refreshing.
+	if stored.RevokedAt != nil {
+		if err := s.tokenRepo.RevokeRefreshTokenFamily(stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token expired")
+	}
+
+	rotated, err := s.tokenRepo.RevokeRefreshToken(stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		if err := s.tokenRepo.RevokeRefreshTokenFamily(stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	if err := s.tokenRepo.TouchSession(stored.FamilyID, clientIP, 0); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, stored.FamilyID)
+}
+
+func (s *AuthService) Logout(accessToken, refreshToken string) error {
+	claims, err := s.parseToken(accessToken)
+	if err != nil {
+		return errors.New("invalid token")
+	}
+
+	userID, err := uuid.Parse(claimString(claims, "user_id"))
+	if err != nil {
+		return errors.New("invalid token")
+	}
+
+	if err := s.revokeAccessToken(claims, userID); err != nil {
+		return err
+	}
+
+	if sessionID, err := uuid.Parse(claimString(claims, "sid")); err == nil {
+		if err := s.tokenRepo.RevokeRefreshTokenFamily(sessionID); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken != "" {
+		stored, err := s.tokenRepo.FindRefreshTokenByHash(hashToken(refreshToken))
+		if err == nil && stored.UserID == userID {
+			if err := s.tokenRepo.RevokeRefreshTokenFamily(stored.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateToken checks the token's signature, its jti against the denylist
+// and its session, then describes the user as currently stored.
+func (s *AuthService) ValidateToken(tokenString, clientIP string) (*model.ValidateResponse, error) {
+	_, user, session, err := s.activeAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
 		return &model.ValidateResponse{Valid: false}, nil
 	}
+	if err := s.tokenRepo.TouchSession(session.ID, clientIP, sessionTouchInterval); err != nil {
+		return nil, err
+	}
 
-	department := ""
-	if dept, ok := claims["department"].(string); ok {
-		department = dept
+	permissions, err := s.roles.Permissions(user.Role)
+	if err != nil {
+		return nil, err
 	}
-	name := ""
-	if n, ok := claims["name"].(string); ok {
-		name = n
+
+	department := ""
+	if user.Department != nil {
+		department = *user.Department
 	}
 
 	return &model.ValidateResponse{
-		Valid:      true,
-		UserID:     claims["user_id"].(string),
-		Role:       claims["role"].(string),
-		Department: department,
-		Name:       name,
+		Valid:       true,
+		UserID:      user.ID.String(),
+		Role:        string(user.Role),
+		Department:  department,
+		Name:        user.Name,
+		Permissions: permissions,
+		SessionID:   session.ID.String(),
 	}, nil
 }
 
+// IntrospectToken applies the same checks as ValidateToken for a third-party
+// resource server. It leaves the session's last-seen details alone, since the
+// caller is not the token holder.
+func (s *AuthService) IntrospectToken(tokenString string) (*model.IntrospectionResponse, error) {
+	claims, user, session, err := s.activeAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return &model.IntrospectionResponse{Active: false}, nil
+	}
+
+	permissions, err := s.roles.Permissions(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.IntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(permissions, " "),
+		TokenType: "Bearer",
+		Username:  user.Email,
+		Sub:       user.ID.String(),
+		Jti:       claimString(claims, "jti"),
+		Role:      string(user.Role),
+		Name:      user.Name,
+		SessionID: session.ID.String(),
+	}
+	if user.Department != nil {
+		response.Department = *user.Department
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		response.Exp = exp.Unix()
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		response.Iat = iat.Unix()
+	}
+
+	return response, nil
+}
+
+// activeAccessToken resolves an access token to its claims, user and
+// session. The user is nil when the token is invalid, revoked, its session
+// has ended or the account is gone or deactivated.
+func (s *AuthService) activeAccessToken(tokenString string) (jwt.MapClaims, *model.User, *model.Session, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, nil, nil, nil
+	}
+
+	jti, err := uuid.Parse(claimString(claims, "jti"))
+	if err != nil {
+		return nil, nil, nil, nil
+	}
+
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(jti)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if revoked {
+		return nil, nil, nil, nil
+	}
+
+	userID, err := uuid.Parse(claimString(claims, "user_id"))
+	if err != nil {
+		return nil, nil, nil, nil
+	}
+
+	sessionID, err := uuid.Parse(claimString(claims, "sid"))
+	if err != nil {
+		return nil, nil, nil, nil
+	}
+	session, err := s.tokenRepo.FindSession(sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, nil, nil, nil
+		}
+		return nil, nil, nil, err
+	}
+	if session.RevokedAt != nil || session.UserID != userID {
+		return nil, nil, nil, nil
+	}
+
+	// Role, department and active state come from the database rather than
+	// the token so deactivation and role changes apply immediately.
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil, nil, nil
+		}
+		return nil, nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, nil, nil
+	}
+
+	return claims, user, session, nil
+}
+
 func (s *AuthService) GetUserByID(id uuid.UUID) (*model.User, error) {
-	return s.userRepo.FindByID(id)
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPermissions(user)
+}
+
+// ListSessions returns the user's signed-in devices, flagging the one the
+// request came from.
+func (s *AuthService) ListSessions(userID, currentSessionID uuid.UUID) ([]model.Session, error) {
+	sessions, err := s.tokenRepo.FindActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one device out. Its refresh tokens stop working and
+// its access tokens are rejected by /validate from now on.
+func (s *AuthService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.tokenRepo.FindSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return s.tokenRepo.RevokeRefreshTokenFamily(sessionID)
 }
 
-func (s *AuthService) generateToken(user *model.User) (string, error) {
+func (s *AuthService) ListRoles() ([]model.RoleDefinition, error) {
+	return s.roles.List()
+}
+
+func (s *AuthService) JWKS() model.JWKS {
+	return s.keyManager.JWKS()
+}
+
+func (s *AuthService) PurgeExpiredTokens() (int64, error) {
+	return s.tokenRepo.DeleteExpired()
+}
+
+// startSession records a new device session and issues its first tokens.
+// The session id doubles as the refresh token family id.
+func (s *AuthService) startSession(user *model.User, client model.ClientInfo) (*model.LoginResponse, error) {
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &model.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		IPAddress:  client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.tokenRepo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session.ID)
+}
+
+func (s *AuthService) issueTokens(user *model.User, familyID uuid.UUID) (*model.LoginResponse, error) {
+	user, err := s.withPermissions(user)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.generateToken(user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stored := &model.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(time.Hour * time.Duration(s.jwtConfig.RefreshTokenHours)),
+		CreatedAt: now,
+	}
+	if err := s.tokenRepo.CreateRefreshToken(stored); err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtConfig.AccessTokenMinutes * 60),
+		User:         user,
+	}, nil
+}
+
+// reissueAccessToken revokes the caller's access token and signs a new one
+// from the user's current profile. The refresh token is left alone.
+func (s *AuthService) reissueAccessToken(user *model.User, accessToken string) (*model.LoginResponse, error) {
+	claims, err := s.parseToken(accessToken)
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+	sessionID, err := uuid.Parse(claimString(claims, "sid"))
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+	if err := s.revokeAccessToken(claims, user.ID); err != nil {
+		return nil, err
+	}
+
+	user, err = s.withPermissions(user)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.generateToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		Token:     token,
+		ExpiresIn: int64(s.jwtConfig.AccessTokenMinutes * 60),
+		User:      user,
+	}, nil
+}
+
+func (s *AuthService) generateToken(user *model.User, sessionID uuid.UUID) (string, error) {
 	department := ""
 	if user.Department != nil {
 		department = *user.Department
 	}
 
 	claims := jwt.MapClaims{
+		"jti":        uuid.New().String(),
 		"user_id":    user.ID.String(),
+		"sid":        sessionID.String(),
 		"email":      user.Email,
 		"name":       user.Name,
 		"role":       string(user.Role),
 		"department": department,
-		"exp":        time.Now().Add(time.Hour * time.Duration(s.jwtConfig.ExpirationHours)).Unix(),
+		"exp":        time.Now().Add(time.Minute * time.Duration(s.jwtConfig.AccessTokenMinutes)).Unix(),
 		"iat":        time.Now().Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtConfig.Secret))
+	return s.keyManager.Sign(claims)
+}
+
+func (s *AuthService) parseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, s.keyManager.Keyfunc,
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}))
+
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid claims")
+	}
+
+	return claims, nil
+}
+
+func (s *AuthService) revokeAccessToken(claims jwt.MapClaims, userID uuid.UUID) error {
+	jti, err := uuid.Parse(claimString(claims, "jti"))
+	if err != nil {
+		return errors.New("invalid token")
+	}
+
+	expiresAt := time.Now().Add(time.Minute * time.Duration(s.jwtConfig.AccessTokenMinutes))
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		expiresAt = exp.Time
+	}
+
+	return s.tokenRepo.RevokeAccessToken(jti, userID, expiresAt)
+}
+
+func (s *AuthService) withPermissions(user *model.User) (*model.User, error) {
+	permissions, err := s.roles.Permissions(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	user.Permissions = permissions
+	return user, nil
+}
+
+func claimString(claims jwt.MapClaims, key string) string {
+	if v, ok := claims[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func generateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
 }
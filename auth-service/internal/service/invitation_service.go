@@ -0,0 +1,140 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"time"
+
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultInvitationHours = 72
+	maxInvitationHours     = 24 * 30
+)
+
+var ErrInvitationNotAllowed = errors.New("not allowed to issue invitations for this role")
+
+type InvitationService struct {
+	inviteRepo *repository.InvitationRepository
+	userRepo   *repository.UserRepository
+	roles      *RoleService
+}
+
+func NewInvitationService(inviteRepo *repository.InvitationRepository, userRepo *repository.UserRepository, roles *RoleService) *InvitationService {
+	return &InvitationService{
+		inviteRepo: inviteRepo,
+		userRepo:   userRepo,
+		roles:      roles,
+	}
+}
+
+// CreateInvitation issues a single-use code. The plain code is only returned
+// here; the database keeps its hash.
+func (s *InvitationService) CreateInvitation(issuerID uuid.UUID, req *model.CreateInvitationRequest) (*model.CreateInvitationResponse, error) {
+	issuer, err := s.userRepo.FindByID(issuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	department, err := s.invitationScope(issuer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := req.ExpiresIn
+	if hours <= 0 {
+		hours = defaultInvitationHours
+	}
+	if hours > maxInvitationHours {
+		hours = maxInvitationHours
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &model.Invitation{
+		ID:         uuid.New(),
+		CodeHash:   hashToken(code),
+		Role:       req.Role,
+		Department: department,
+		CreatedBy:  issuer.ID,
+		ExpiresAt:  now.Add(time.Hour * time.Duration(hours)),
+		CreatedAt:  now,
+	}
+
+	if err := s.inviteRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	return &model.CreateInvitationResponse{
+		Code:       code,
+		Invitation: *invitation,
+	}, nil
+}
+
+func (s *InvitationService) ListInvitations(issuerID uuid.UUID) ([]model.Invitation, error) {
+	invitations, err := s.inviteRepo.FindByCreator(issuerID)
+	if err != nil {
+		return nil, err
+	}
+	if invitations == nil {
+		invitations = []model.Invitation{}
+	}
+	return invitations, nil
+}
+
+// invitationScope decides whether the issuer may invite the requested role
+// and which department the invitee ends up in. Department admins can only
+// bring in colleagues for their own department, either fellow admins or the
+// department's officers; user.manage holders may invite into any role.
+func (s *InvitationService) invitationScope(issuer *model.User, req *model.CreateInvitationRequest) (*string, error) {
+	canManage, err := s.roles.HasPermission(issuer.Role, model.PermUserManage)
+	if err != nil {
+		return nil, err
+	}
+	if canManage {
+		role, err := s.roles.Get(req.Role)
+		if err != nil {
+			return nil, ErrInvitationNotAllowed
+		}
+		if req.Department != "" {
+			return &req.Department, nil
+		}
+		return role.Department, nil
+	}
+
+	allowed, err := s.roles.HasPermission(issuer.Role, model.PermUserInvite)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrInvitationNotAllowed
+	}
+	if req.Role != issuer.Role {
+		role, err := s.roles.Get(req.Role)
+		if err != nil || role.IsAdmin || role.Department == nil ||
+			issuer.Department == nil || *role.Department != *issuer.Department {
+			return nil, ErrInvitationNotAllowed
+		}
+	}
+	if req.Department != "" && (issuer.Department == nil || req.Department != *issuer.Department) {
+		return nil, ErrInvitationNotAllowed
+	}
+	return issuer.Department, nil
+}
+
+func generateInviteCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"auth-service/config"
+	"auth-service/internal/model"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	rsaKeyBits = 2048
+)
+
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+}
+
+type KeyManager struct {
+	keys      map[string]*signingKey
+	order     []string
+	activeKID string
+}
+
+// NewKeyManager loads every configured key pair. A missing key file is
+// generated and written to disk so a fresh deployment works out of the box.
+func NewKeyManager(cfg config.JWTConfig) (*KeyManager, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+
+	km := &KeyManager{
+		keys:      make(map[string]*signingKey),
+		activeKID: cfg.ActiveKeyID,
+	}
+
+	for _, kc := range cfg.Keys {
+		if kc.KeyID == "" {
+			return nil, errors.New("signing key without kid")
+		}
+		if _, exists := km.keys[kc.KeyID]; exists {
+			return nil, fmt.Errorf("duplicate kid %q", kc.KeyID)
+		}
+
+		key, err := loadSigningKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kc.KeyID, err)
+		}
+		km.keys[kc.KeyID] = key
+		km.order = append(km.order, kc.KeyID)
+	}
+
+	if km.activeKID == "" {
+		km.activeKID = cfg.Keys[0].KeyID
+	}
+	if _, ok := km.keys[km.activeKID]; !ok {
+		return nil, fmt.Errorf("active kid %q is not configured", km.activeKID)
+	}
+
+	return km, nil
+}
+
+func (km *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	key := km.keys[km.activeKID]
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.privateKey)
+}
+
+// Keyfunc resolves the verification key from the token's kid header and
+// rejects tokens whose alg doesn't match the algorithm of that key.
+func (km *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := km.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+	return key.publicKey, nil
+}
+
+func (km *KeyManager) JWKS() model.JWKS {
+	jwks := model.JWKS{Keys: []model.JWK{}}
+	for _, kid := range km.order {
+		key := km.keys[kid]
+
+		jwk := model.JWK{
+			KeyID:     key.kid,
+			Use:       "sig",
+			Algorithm: key.method.Alg(),
+		}
+		switch pub := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+func loadSigningKey(kc config.SigningKeyConfig) (*signingKey, error) {
+	var method jwt.SigningMethod
+	switch kc.Algorithm {
+	case AlgorithmRS256:
+		method = jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", kc.Algorithm)
+	}
+
+	pemBytes, err := os.ReadFile(kc.PrivateKeyFile)
+	if os.IsNotExist(err) {
+		pemBytes, err = generateKeyFile(kc.PrivateKeyFile, kc.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("generated new %s signing key %s at %s", kc.Algorithm, kc.KeyID, kc.PrivateKeyFile)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var signer crypto.Signer
+	switch kc.Algorithm {
+	case AlgorithmRS256:
+		signer, err = jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+	case AlgorithmEdDSA:
+		var pk crypto.PrivateKey
+		pk, err = jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err == nil {
+			signer, _ = pk.(crypto.Signer)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if signer == nil {
+		return nil, errors.New("private key is not a signer")
+	}
+
+	return &signingKey{
+		kid:        kc.KeyID,
+		method:     method,
+		privateKey: signer,
+		publicKey:  signer.Public(),
+	}, nil
+}
+
+func generateKeyFile(path, algorithm string) ([]byte, error) {
+	var privateKey crypto.PrivateKey
+	var err error
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pemBytes, 0o600); err != nil {
+		return nil, err
+	}
+
+	return pemBytes, nil
+}
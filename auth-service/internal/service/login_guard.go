@@ -0,0 +1,240 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultMaxLoginFailures   = 5
+	defaultLockoutMinutes     = 15
+	defaultLoginWindowMinutes = 15
+	defaultMaxIPFailures      = 30
+	defaultBaseDelayMillis    = 500
+	defaultMaxDelaySeconds    = 30
+)
+
+var (
+	ErrAccountLocked    = errors.New("account is temporarily locked")
+	ErrUnlockNotAllowed = errors.New("not allowed to unlock this account")
+
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+)
+
+// LoginThrottledError is returned when a login attempt arrives before the
+// progressive delay for that email or IP has passed.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry in %d seconds", int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+type LoginGuard struct {
+	attemptRepo *repository.LoginAttemptRepository
+	userRepo    *repository.UserRepository
+	auditRepo   *repository.AuditRepository
+	roles       *RoleService
+	cfg         config.LoginConfig
+}
+
+func NewLoginGuard(attemptRepo *repository.LoginAttemptRepository, userRepo *repository.UserRepository, auditRepo *repository.AuditRepository, roles *RoleService, cfg config.LoginConfig) *LoginGuard {
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = defaultMaxLoginFailures
+	}
+	if cfg.LockoutMinutes <= 0 {
+		cfg.LockoutMinutes = defaultLockoutMinutes
+	}
+	if cfg.WindowMinutes <= 0 {
+		cfg.WindowMinutes = defaultLoginWindowMinutes
+	}
+	if cfg.MaxIPFailures <= 0 {
+		cfg.MaxIPFailures = defaultMaxIPFailures
+	}
+	if cfg.BaseDelayMillis <= 0 {
+		cfg.BaseDelayMillis = defaultBaseDelayMillis
+	}
+	if cfg.MaxDelaySeconds <= 0 {
+		cfg.MaxDelaySeconds = defaultMaxDelaySeconds
+	}
+
+	return &LoginGuard{
+		attemptRepo: attemptRepo,
+		userRepo:    userRepo,
+		auditRepo:   auditRepo,
+		roles:       roles,
+		cfg:         cfg,
+	}
+}
+
+// Check rejects an attempt before the password is even looked at: either
+// the IP has burned through its failure budget, or the email is still
+// inside the back-off that doubles with every consecutive failure.
+func (g *LoginGuard) Check(email, ip string) error {
+	window := time.Duration(g.cfg.WindowMinutes) * time.Minute
+	since := time.Now().Add(-window)
+
+	ipFailures, err := g.attemptRepo.RecentIPFailures(ip, since)
+	if err != nil {
+		return err
+	}
+	if ipFailures >= g.cfg.MaxIPFailures {
+		return &LoginThrottledError{RetryAfter: window}
+	}
+
+	failures, lastFailure, err := g.attemptRepo.RecentEmailFailures(email, since)
+	if err != nil {
+		return err
+	}
+	if failures == 0 || lastFailure == nil {
+		return nil
+	}
+
+	if wait := time.Until(lastFailure.Add(g.delay(failures))); wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// CheckLocked reports whether the account is currently locked out.
+func (g *LoginGuard) CheckLocked(user *model.User) error {
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return ErrAccountLocked
+	}
+	return nil
+}
+
+// RecordFailure logs the attempt and, when the user exists, counts it
+// toward the account lockout. user is nil for unknown emails.
+func (g *LoginGuard) RecordFailure(user *model.User, email, ip string) {
+	if err := g.attemptRepo.Record(email, ip, false); err != nil {
+		log.Printf("login guard: record attempt: %v", err)
+	}
+	if user == nil {
+		return
+	}
+
+	lockFor := time.Duration(g.cfg.LockoutMinutes) * time.Minute
+	lockedUntil, err := g.userRepo.RecordFailedLogin(user.ID, g.cfg.MaxFailures, lockFor)
+	if err != nil {
+		log.Printf("login guard: record failure for %s: %v", user.ID, err)
+		return
+	}
+	if lockedUntil == nil {
+		return
+	}
+
+	writeAudit(g.auditRepo, &model.AuditEvent{
+		Event:     model.AuditAccountLocked,
+		UserID:    &user.ID,
+		Email:     user.Email,
+		IPAddress: ip,
+		Details: map[string]interface{}{
+			"failures":     g.cfg.MaxFailures,
+			"locked_until": lockedUntil,
+		},
+	})
+}
+
+func (g *LoginGuard) RecordSuccess(user *model.User, ip string) {
+	if err := g.attemptRepo.Record(user.Email, ip, true); err != nil {
+		log.Printf("login guard: record attempt: %v", err)
+	}
+	if err := g.userRepo.ResetFailedLogins(user.ID); err != nil {
+		log.Printf("login guard: reset failures for %s: %v", user.ID, err)
+	}
+}
+
+// CheckCurrentPassword confirms a signed-in user's password before a
+// sensitive change. It goes through the same throttling as /login so a
+// stolen access token can't be used to brute-force the password.
+func (g *LoginGuard) CheckCurrentPassword(user *model.User, password, ip string) error {
+	if err := g.Check(user.Email, ip); err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		g.RecordFailure(user, user.Email, ip)
+		return ErrInvalidCurrentPassword
+	}
+	return nil
+}
+
+// Unlock lifts a lockout early. Holders of user.unlock may unlock citizens
+// and admin accounts in their own department; user.manage lifts the
+// department restriction.
+func (g *LoginGuard) Unlock(userID, actorID uuid.UUID, ip string) error {
+	actor, err := g.userRepo.FindByID(actorID)
+	if err != nil {
+		return err
+	}
+	allowed, err := g.roles.HasPermission(actor.Role, model.PermUserUnlock)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrUnlockNotAllowed
+	}
+
+	user, err := g.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	targetIsAdmin, err := g.roles.IsAdmin(user.Role)
+	if err != nil {
+		return err
+	}
+	if targetIsAdmin && !sameDepartment(actor.Department, user.Department) {
+		canManage, err := g.roles.HasPermission(actor.Role, model.PermUserManage)
+		if err != nil {
+			return err
+		}
+		if !canManage {
+			return ErrUnlockNotAllowed
+		}
+	}
+	if err := g.userRepo.Unlock(userID); err != nil {
+		return err
+	}
+
+	writeAudit(g.auditRepo, &model.AuditEvent{
+		Event:     model.AuditAccountUnlocked,
+		UserID:    &user.ID,
+		ActorID:   &actorID,
+		Email:     user.Email,
+		IPAddress: ip,
+	})
+	return nil
+}
+
+// PurgeAttempts drops attempt rows that no longer fall inside any window.
+func (g *LoginGuard) PurgeAttempts() (int64, error) {
+	return g.attemptRepo.DeleteOlderThan(24 * time.Hour)
+}
+
+func (g *LoginGuard) delay(failures int) time.Duration {
+	maxDelay := time.Duration(g.cfg.MaxDelaySeconds) * time.Second
+	if failures > 16 {
+		return maxDelay
+	}
+
+	d := time.Duration(g.cfg.BaseDelayMillis) * time.Millisecond << (failures - 1)
+	if d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
+
+func sameDepartment(a, b *string) bool {
+	return a != nil && b != nil && *a == *b
+}
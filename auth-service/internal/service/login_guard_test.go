@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auth-service/config"
+)
+
+func TestLoginGuardDelay(t *testing.T) {
+	defaults := NewLoginGuard(nil, nil, nil, nil, config.LoginConfig{})
+	custom := NewLoginGuard(nil, nil, nil, nil, config.LoginConfig{BaseDelayMillis: 100, MaxDelaySeconds: 1})
+
+	tests := []struct {
+		name     string
+		guard    *LoginGuard
+		failures int
+		want     time.Duration
+	}{
+		{"defaults first failure", defaults, 1, 500 * time.Millisecond},
+		{"defaults second failure", defaults, 2, time.Second},
+		{"defaults doubles", defaults, 5, 8 * time.Second},
+		{"defaults last below cap", defaults, 6, 16 * time.Second},
+		{"defaults capped", defaults, 7, 30 * time.Second},
+		{"defaults many failures", defaults, 17, 30 * time.Second},
+		{"defaults huge failure count", defaults, 1000, 30 * time.Second},
+		{"custom first failure", custom, 1, 100 * time.Millisecond},
+		{"custom doubles", custom, 4, 800 * time.Millisecond},
+		{"custom capped", custom, 5, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := tt.guard.delay(tt.failures); got != tt.want {
+			t.Errorf("%s: delay(%d) = %s, want %s", tt.name, tt.failures, got, tt.want)
+		}
+	}
+}
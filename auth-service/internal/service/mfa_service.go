@@ -0,0 +1,348 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMFAIssuer           = "CityConnect"
+	defaultMFAChallengeMinutes = 5
+	recoveryCodeCount          = 10
+)
+
+var (
+	ErrInvalidMFACode      = errors.New("invalid authentication code")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrMFARequired         = errors.New("two-factor authentication is required for this role")
+	ErrInvalidMFAChallenge = errors.New("invalid or expired challenge token")
+)
+
+type MFAService struct {
+	mfaRepo   *repository.MFARepository
+	userRepo  *repository.UserRepository
+	acctRepo  *repository.AccountTokenRepository
+	auditRepo *repository.AuditRepository
+	roles     *RoleService
+	cfg       config.MFAConfig
+}
+
+func NewMFAService(mfaRepo *repository.MFARepository, userRepo *repository.UserRepository, acctRepo *repository.AccountTokenRepository, auditRepo *repository.AuditRepository, roles *RoleService, cfg config.MFAConfig) *MFAService {
+	if cfg.Issuer == "" {
+		cfg.Issuer = defaultMFAIssuer
+	}
+	if cfg.ChallengeMinutes <= 0 {
+		cfg.ChallengeMinutes = defaultMFAChallengeMinutes
+	}
+
+	return &MFAService{
+		mfaRepo:   mfaRepo,
+		userRepo:  userRepo,
+		acctRepo:  acctRepo,
+		auditRepo: auditRepo,
+		roles:     roles,
+		cfg:       cfg,
+	}
+}
+
+// RequiredFor reports whether policy forces MFA on this user.
+func (s *MFAService) RequiredFor(user *model.User) (bool, error) {
+	if !s.cfg.RequiredForAdmins {
+		return false, nil
+	}
+	return s.roles.IsAdmin(user.Role)
+}
+
+func (s *MFAService) IsEnabled(userID uuid.UUID) (bool, error) {
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return mfa.EnabledAt != nil, nil
+}
+
+func (s *MFAService) Status(userID uuid.UUID) (*model.MFAStatusResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := s.RequiredFor(user)
+	if err != nil {
+		return nil, err
+	}
+	status := &model.MFAStatusResponse{Required: required}
+
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+
+	status.Enabled = mfa.EnabledAt != nil
+	status.Pending = mfa.EnabledAt == nil
+	if status.Enabled {
+		status.RecoveryCodesRemaining, err = s.mfaRepo.CountUnusedRecoveryCodes(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// Enroll generates a new pending secret. It only takes effect once a code
+// from it is confirmed through Activate.
+func (s *MFAService) Enroll(userID uuid.UUID) (*model.MFAEnrollResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.mfaRepo.SavePending(user.ID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	return &model.MFAEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Activate confirms a pending secret and returns a fresh set of recovery
+// codes, which are only ever shown this once.
+func (s *MFAService) Activate(userID uuid.UUID, code string) ([]string, error) {
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok := verifyTOTP(mfa.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.mfaRepo.BeginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	enabled, err := s.mfaRepo.EnableInTransaction(tx, userID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodesInTransaction(tx, userID, hashes); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:  model.AuditMFAEnabled,
+		UserID: &userID,
+	})
+	return codes, nil
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code.
+func (s *MFAService) Verify(userID uuid.UUID, code string) error {
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+	if mfa.EnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		step, ok := verifyTOTP(mfa.Secret, code, time.Now())
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		used, err := s.mfaRepo.UseStep(userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *MFAService) Disable(userID uuid.UUID, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	required, err := s.RequiredFor(user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequired
+	}
+
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	if err := s.mfaRepo.Delete(userID); err != nil {
+		return err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:  model.AuditMFADisabled,
+		UserID: &userID,
+		Email:  user.Email,
+	})
+	return nil
+}
+
+func (s *MFAService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.mfaRepo.BeginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.mfaRepo.ReplaceRecoveryCodesInTransaction(tx, userID, hashes); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// IssueChallenge creates the short-lived token a client exchanges, together
+// with a second factor, for real tokens at /login/mfa.
+func (s *MFAService) IssueChallenge(userID uuid.UUID) (string, error) {
+	raw, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := &model.AccountToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   model.PurposeMFAChallenge,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(time.Duration(s.cfg.ChallengeMinutes) * time.Minute),
+		CreatedAt: now,
+	}
+	if err := s.acctRepo.Create(token); err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// ChallengeUser resolves a challenge token without consuming it, so a
+// mistyped code doesn't force the user back to the password step.
+func (s *MFAService) ChallengeUser(rawToken string) (*model.User, error) {
+	token, err := s.acctRepo.FindValid(hashToken(rawToken), model.PurposeMFAChallenge)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+	return s.userRepo.FindByID(token.UserID)
+}
+
+func (s *MFAService) ConsumeChallenge(rawToken string) error {
+	tx, err := s.acctRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := s.acctRepo.ConsumeInTransaction(tx, hashToken(rawToken), model.PurposeMFAChallenge); err != nil {
+		return ErrInvalidMFAChallenge
+	}
+	return tx.Commit()
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 6)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
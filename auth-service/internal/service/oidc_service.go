@@ -0,0 +1,431 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/model"
+	"auth-service/internal/oidc"
+	"auth-service/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	oidcStateTTL     = 10 * time.Minute
+	oidcLoginCodeTTL = time.Minute
+)
+
+var (
+	ErrOIDCDisabled     = errors.New("oidc login is not enabled")
+	ErrOIDCInvalidState = errors.New("invalid or expired login state")
+	ErrOIDCInvalidCode  = errors.New("invalid or expired login code")
+	ErrOIDCEmailInUse   = errors.New("email is already registered to a local account")
+)
+
+// OIDCService logs users in through the city's identity provider. The
+// provider callback never hands tokens to the browser directly: it issues a
+// short-lived one-time code that the frontend exchanges at /oidc/token.
+type OIDCService struct {
+	provider    *oidc.Provider
+	oidcRepo    *repository.OIDCRepository
+	userRepo    *repository.UserRepository
+	acctRepo    *repository.AccountTokenRepository
+	auditRepo   *repository.AuditRepository
+	roles       *RoleService
+	authService *AuthService
+	cfg         config.OIDCConfig
+}
+
+func NewOIDCService(provider *oidc.Provider, oidcRepo *repository.OIDCRepository, userRepo *repository.UserRepository, acctRepo *repository.AccountTokenRepository, auditRepo *repository.AuditRepository, roles *RoleService, authService *AuthService, cfg config.OIDCConfig) *OIDCService {
+	if cfg.DefaultRole == "" {
+		cfg.DefaultRole = string(model.DefaultRole)
+	}
+
+	return &OIDCService{
+		provider:    provider,
+		oidcRepo:    oidcRepo,
+		userRepo:    userRepo,
+		acctRepo:    acctRepo,
+		auditRepo:   auditRepo,
+		roles:       roles,
+		authService: authService,
+		cfg:         cfg,
+	}
+}
+
+// LoginURL starts an authorization request and returns the provider URL to
+// redirect the browser to.
+func (s *OIDCService) LoginURL() (string, error) {
+	if !s.cfg.Enabled {
+		return "", ErrOIDCDisabled
+	}
+
+	state, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.oidcRepo.CreateState(&model.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	return s.provider.AuthCodeURL(state, nonce, verifier)
+}
+
+// Callback completes the authorization code flow and returns where to send
+// the browser next: the frontend, carrying either a one-time login code or
+// an error.
+func (s *OIDCService) Callback(code, state, providerError string) string {
+	loginCode, err := s.callback(code, state, providerError)
+	if err != nil {
+		log.Printf("oidc callback: %v", err)
+		return s.frontendRedirect(url.Values{"error": {publicOIDCError(err)}})
+	}
+	return s.frontendRedirect(url.Values{"code": {loginCode}})
+}
+
+func (s *OIDCService) callback(code, state, providerError string) (string, error) {
+	if !s.cfg.Enabled {
+		return "", ErrOIDCDisabled
+	}
+	if providerError != "" {
+		return "", fmt.Errorf("provider returned error: %s", providerError)
+	}
+	if code == "" || state == "" {
+		return "", ErrOIDCInvalidState
+	}
+
+	pending, err := s.oidcRepo.ConsumeState(hashToken(state))
+	if err != nil {
+		if errors.Is(err, repository.ErrStateNotFound) {
+			return "", ErrOIDCInvalidState
+		}
+		return "", err
+	}
+
+	rawIDToken, err := s.provider.Exchange(code, pending.CodeVerifier)
+	if err != nil {
+		return "", err
+	}
+	claims, err := s.provider.VerifyIDToken(rawIDToken, pending.Nonce)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := s.resolveUser(claims)
+	if err != nil {
+		return "", err
+	}
+	if !user.IsActive {
+		return "", ErrAccountDeactivated
+	}
+
+	return s.issueLoginCode(user.ID)
+}
+
+// ExchangeLoginCode turns the one-time code from the callback into a
+// CityConnect session. Local MFA is skipped; the provider is responsible
+// for the strength of its own login.
+func (s *OIDCService) ExchangeLoginCode(code string, client model.ClientInfo) (*model.LoginResponse, error) {
+	if !s.cfg.Enabled {
+		return nil, ErrOIDCDisabled
+	}
+
+	tx, err := s.acctRepo.BeginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	token, err := s.acctRepo.ConsumeInTransaction(tx, hashToken(code), model.PurposeOIDCLogin)
+	if err != nil {
+		return nil, ErrOIDCInvalidCode
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	return s.authService.startSession(user, client)
+}
+
+func (s *OIDCService) PurgeExpiredStates() (int64, error) {
+	return s.oidcRepo.DeleteExpiredStates()
+}
+
+// resolveUser finds the local account for the external identity. Unknown
+// identities are linked to an existing account only when the provider
+// vouches for the email; otherwise a new account is created.
+func (s *OIDCService) resolveUser(claims jwt.MapClaims) (*model.User, error) {
+	issuer := s.provider.Issuer()
+	subject, _ := claims["sub"].(string)
+	email := strings.ToLower(strings.TrimSpace(claimString(claims, "email")))
+	emailVerified, _ := claims["email_verified"].(bool)
+
+	role, err := s.mapRole(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.oidcRepo.FindIdentity(issuer, subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.oidcRepo.TouchIdentity(issuer, subject, email); err != nil {
+			log.Printf("oidc: touch identity %s: %v", subject, err)
+		}
+		return s.syncRole(user, role)
+	}
+	if !errors.Is(err, repository.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	if email == "" {
+		return nil, errors.New("id token has no email claim")
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	switch {
+	case err == nil:
+		if !emailVerified {
+			return nil, ErrOIDCEmailInUse
+		}
+		if err := s.link(user, issuer, subject, email, nil); err != nil {
+			return nil, err
+		}
+		return s.syncRole(user, role)
+	case errors.Is(err, repository.ErrUserNotFound):
+		return s.createUser(claims, issuer, subject, email, role)
+	default:
+		return nil, err
+	}
+}
+
+func (s *OIDCService) createUser(claims jwt.MapClaims, issuer, subject, email string, role *model.RoleDefinition) (*model.User, error) {
+	// Federated accounts sign in through the provider only; the random
+	// password is never handed out.
+	random, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	name := claimString(claims, "name")
+	if name == "" {
+		name = email
+	}
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        email,
+		PasswordHash: string(hashed),
+		Name:         name,
+		Role:         role.Name,
+		Department:   role.Department,
+		IsActive:     true,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.link(user, issuer, subject, email, func(tx *sql.Tx) error {
+		return s.userRepo.CreateInTransaction(tx, user)
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// link records the identity for user, optionally creating the user in the
+// same transaction. The provider vouched for the email, so it counts as
+// verified.
+func (s *OIDCService) link(user *model.User, issuer, subject, email string, createUser func(tx *sql.Tx) error) error {
+	tx, err := s.oidcRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if createUser != nil {
+		if err := createUser(tx); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if err := s.oidcRepo.CreateIdentityInTransaction(tx, &model.UserIdentity{
+		Issuer:      issuer,
+		Subject:     subject,
+		UserID:      user.ID,
+		Email:       email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}); err != nil {
+		return err
+	}
+	if err := s.userRepo.MarkEmailVerifiedInTransaction(tx, user.ID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:  model.AuditOIDCLinked,
+		UserID: &user.ID,
+		Email:  email,
+		Details: map[string]interface{}{
+			"issuer":  issuer,
+			"subject": subject,
+			"created": createUser != nil,
+		},
+	})
+	return nil
+}
+
+// mapRole picks the local role for the identity: the first configured
+// mapping whose value appears in the role claim, else the default role.
+func (s *OIDCService) mapRole(claims jwt.MapClaims) (*model.RoleDefinition, error) {
+	values := claimValues(claims, s.cfg.RoleClaim)
+
+	roleName := s.cfg.DefaultRole
+	for _, mapping := range s.cfg.RoleMappings {
+		if values[mapping.Value] {
+			roleName = mapping.Role
+			break
+		}
+	}
+
+	role, err := s.roles.Get(model.Role(roleName))
+	if err != nil {
+		return nil, fmt.Errorf("oidc role mapping: %s: %w", roleName, err)
+	}
+	return role, nil
+}
+
+// syncRole applies the mapped role on every login when sync_roles is on, so
+// group changes at the provider carry over to CityConnect.
+func (s *OIDCService) syncRole(user *model.User, role *model.RoleDefinition) (*model.User, error) {
+	if !s.cfg.SyncRoles || (user.Role == role.Name && equalDepartment(user.Department, role.Department)) {
+		return user, nil
+	}
+
+	oldRole, oldDepartment := user.Role, user.Department
+	if err := s.userRepo.UpdateRole(user.ID, role.Name, role.Department); err != nil {
+		return nil, err
+	}
+	user.Role, user.Department = role.Name, role.Department
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:  model.AuditRoleChanged,
+		UserID: &user.ID,
+		Email:  user.Email,
+		Details: map[string]interface{}{
+			"old_role":       oldRole,
+			"old_department": oldDepartment,
+			"new_role":       role.Name,
+			"new_department": role.Department,
+			"source":         "oidc",
+		},
+	})
+	return user, nil
+}
+
+func (s *OIDCService) issueLoginCode(userID uuid.UUID) (string, error) {
+	raw, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.acctRepo.Create(&model.AccountToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   model.PurposeOIDCLogin,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(oidcLoginCodeTTL),
+		CreatedAt: now,
+	}); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (s *OIDCService) frontendRedirect(params url.Values) string {
+	separator := "?"
+	if strings.Contains(s.cfg.PostLoginRedirect, "?") {
+		separator = "&"
+	}
+	return s.cfg.PostLoginRedirect + separator + params.Encode()
+}
+
+// publicOIDCError is the reason shown to the user; details stay in the log.
+func publicOIDCError(err error) string {
+	switch {
+	case errors.Is(err, ErrAccountDeactivated):
+		return "account_deactivated"
+	case errors.Is(err, ErrOIDCEmailInUse):
+		return "email_in_use"
+	case errors.Is(err, ErrOIDCInvalidState):
+		return "invalid_state"
+	default:
+		return "login_failed"
+	}
+}
+
+// claimValues reads a string or string-array claim into a set.
+func claimValues(claims jwt.MapClaims, key string) map[string]bool {
+	values := make(map[string]bool)
+	switch v := claims[key].(type) {
+	case string:
+		for _, part := range strings.Fields(v) {
+			values[part] = true
+		}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values[s] = true
+			}
+		}
+	}
+	return values
+}
+
+func equalDepartment(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/userdata"
+
+	"github.com/google/uuid"
+)
+
+var ErrAccountDeletionNotAllowed = errors.New("staff accounts can only be removed by an administrator")
+
+// PrivacyService answers a citizen's personal data requests (UU PDP): a
+// full export and erasure of the account.
+type PrivacyService struct {
+	userRepo    *repository.UserRepository
+	tokenRepo   *repository.TokenRepository
+	oidcRepo    *repository.OIDCRepository
+	auditRepo   *repository.AuditRepository
+	authService *AuthService
+	loginGuard  *LoginGuard
+	userData    *userdata.Client
+}
+
+func NewPrivacyService(userRepo *repository.UserRepository, tokenRepo *repository.TokenRepository, oidcRepo *repository.OIDCRepository, auditRepo *repository.AuditRepository, authService *AuthService, loginGuard *LoginGuard, userData *userdata.Client) *PrivacyService {
+	return &PrivacyService{
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		oidcRepo:    oidcRepo,
+		auditRepo:   auditRepo,
+		authService: authService,
+		loginGuard:  loginGuard,
+		userData:    userData,
+	}
+}
+
+// Export collects the user's data from auth-service, report-service and
+// notification-service. It fails rather than hand out a partial export.
+func (s *PrivacyService) Export(userID uuid.UUID) (*model.AccountExport, error) {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.tokenRepo.FindActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := s.oidcRepo.FindIdentitiesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := s.auditRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.userData.Reports(userID)
+	if err != nil {
+		return nil, err
+	}
+	notifications, err := s.userData.Notifications(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AccountExport{
+		ExportedAt:       time.Now(),
+		Profile:          user,
+		Sessions:         sessions,
+		LinkedIdentities: identities,
+		AccountActivity:  activity,
+		Reports:          reports.Reports,
+		Votes:            reports.Votes,
+		Comments:         reports.Comments,
+		Notifications:    notifications.Notifications,
+	}, nil
+}
+
+// DeleteAccount erases a citizen account. Reports must be kept for the
+// audit trail, so report-service first turns them into anonymous-style
+// reports; everything else tied to the user goes with the users row.
+func (s *PrivacyService) DeleteAccount(userID uuid.UUID, req *model.DeleteAccountRequest, clientIP string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Role != model.DefaultRole {
+		return ErrAccountDeletionNotAllowed
+	}
+	if err := s.loginGuard.CheckCurrentPassword(user, req.CurrentPassword, clientIP); err != nil {
+		return err
+	}
+
+	// Lock the account out before touching its reports so nothing new is
+	// filed between anonymising and deleting: /validate turns inactive users
+	// away at once. Should the erasure fail, the account is let back in so
+	// the user can try again.
+	if err := s.userRepo.SetActive(userID, false); err != nil {
+		return err
+	}
+	deleted := false
+	defer func() {
+		if deleted {
+			return
+		}
+		if err := s.userRepo.SetActive(userID, true); err != nil {
+			log.Printf("privacy: reactivate %s after failed deletion: %v", userID, err)
+		}
+	}()
+	if err := s.tokenRepo.RevokeAllRefreshTokens(userID); err != nil {
+		return err
+	}
+
+	// Reports reference users without a cascade, so this has to succeed
+	// before the row can go
+	anonymised, err := s.userData.AnonymiseReports(userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.userRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.auditRepo.ForgetEmailInTransaction(tx, userID); err != nil {
+		return err
+	}
+	if err := s.userRepo.DeleteInTransaction(tx, userID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	deleted = true
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event: model.AuditAccountDeleted,
+		Details: map[string]interface{}{
+			"user_id":            userID,
+			"anonymised_reports": anonymised,
+		},
+	})
+	return nil
+}
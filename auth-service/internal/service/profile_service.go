@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrEmptyName = errors.New("name cannot be empty")
+
+// ProfileService handles changes users make to their own account. Every
+// change hands back a fresh access token so claims such as the name don't
+// lag behind the database.
+type ProfileService struct {
+	userRepo       *repository.UserRepository
+	tokenRepo      *repository.TokenRepository
+	auditRepo      *repository.AuditRepository
+	authService    *AuthService
+	accountService *AccountService
+	loginGuard     *LoginGuard
+}
+
+func NewProfileService(userRepo *repository.UserRepository, tokenRepo *repository.TokenRepository, auditRepo *repository.AuditRepository, authService *AuthService, accountService *AccountService, loginGuard *LoginGuard) *ProfileService {
+	return &ProfileService{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		auditRepo:      auditRepo,
+		authService:    authService,
+		accountService: accountService,
+		loginGuard:     loginGuard,
+	}
+}
+
+// UpdateProfile renames the user and/or starts an email change. The new
+// email is only stored as pending until its confirmation link is opened.
+func (s *ProfileService) UpdateProfile(userID uuid.UUID, accessToken string, req *model.UpdateProfileRequest, clientIP string) (*model.LoginResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, ErrEmptyName
+		}
+		if name != user.Name {
+			if err := s.userRepo.UpdateName(userID, name); err != nil {
+				return nil, err
+			}
+			user.Name = name
+		}
+	}
+
+	if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+		if err := s.loginGuard.CheckCurrentPassword(user, req.CurrentPassword, clientIP); err != nil {
+			return nil, err
+		}
+
+		exists, err := s.userRepo.EmailExists(*req.Email)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrEmailInUse
+		}
+
+		if err := s.accountService.RequestEmailChange(user, *req.Email); err != nil {
+			return nil, err
+		}
+		user.PendingEmail = req.Email
+	}
+
+	return s.authService.reissueAccessToken(user, accessToken)
+}
+
+// ChangePassword requires the current password, ends every session
+// including the caller's and returns tokens for a fresh session.
+func (s *ProfileService) ChangePassword(userID uuid.UUID, req *model.ChangePasswordRequest, client model.ClientInfo) (*model.LoginResponse, error) {
+	clientIP := client.IP
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginGuard.CheckCurrentPassword(user, req.CurrentPassword, clientIP); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdatePassword(userID, string(hashedPassword)); err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.RevokeAllRefreshTokens(userID); err != nil {
+		return nil, err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:     model.AuditPasswordChanged,
+		UserID:    &userID,
+		ActorID:   &userID,
+		Email:     user.Email,
+		IPAddress: clientIP,
+	})
+
+	return s.authService.startSession(user, client)
+}
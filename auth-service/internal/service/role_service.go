@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Roles are read on every /validate, so they are cached for a short while;
+// edits to the role tables show up within roleCacheTTL.
+const roleCacheTTL = time.Minute
+
+var ErrRoleNotFound = errors.New("role not found")
+
+type RoleService struct {
+	roleRepo *repository.RoleRepository
+
+	mu       sync.RWMutex
+	roles    map[model.Role]*model.RoleDefinition
+	loadedAt time.Time
+}
+
+func NewRoleService(roleRepo *repository.RoleRepository) *RoleService {
+	return &RoleService{roleRepo: roleRepo}
+}
+
+func (s *RoleService) Get(name model.Role) (*model.RoleDefinition, error) {
+	roles, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	role, ok := roles[name]
+	if !ok {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (s *RoleService) List() ([]model.RoleDefinition, error) {
+	return s.roleRepo.FindAll()
+}
+
+// Permissions returns the permission names granted to a role. Unknown roles
+// get none rather than an error so a stale token simply loses access.
+func (s *RoleService) Permissions(name model.Role) ([]string, error) {
+	role, err := s.Get(name)
+	if err != nil {
+		if errors.Is(err, ErrRoleNotFound) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	return role.Permissions, nil
+}
+
+func (s *RoleService) HasPermission(name model.Role, permission string) (bool, error) {
+	role, err := s.Get(name)
+	if err != nil {
+		if errors.Is(err, ErrRoleNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return role.HasPermission(permission), nil
+}
+
+func (s *RoleService) IsAdmin(name model.Role) (bool, error) {
+	role, err := s.Get(name)
+	if err != nil {
+		if errors.Is(err, ErrRoleNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return role.IsAdmin, nil
+}
+
+func (s *RoleService) PermissionExists(name string) (bool, error) {
+	return s.roleRepo.PermissionExists(name)
+}
+
+func (s *RoleService) DepartmentExists(code string) (bool, error) {
+	return s.roleRepo.DepartmentExists(code)
+}
+
+// authorizeActor loads the acting user and checks that their role grants
+// permission.
+func authorizeActor(userRepo *repository.UserRepository, roles *RoleService, actorID uuid.UUID, permission string) (*model.User, error) {
+	actor, err := userRepo.FindByID(actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := roles.HasPermission(actor.Role, permission)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrPermissionDenied
+	}
+	return actor, nil
+}
+
+func (s *RoleService) load() (map[model.Role]*model.RoleDefinition, error) {
+	s.mu.RLock()
+	if s.roles != nil && time.Since(s.loadedAt) < roleCacheTTL {
+		roles := s.roles
+		s.mu.RUnlock()
+		return roles, nil
+	}
+	s.mu.RUnlock()
+
+	list, err := s.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[model.Role]*model.RoleDefinition, len(list))
+	for i := range list {
+		roles[list[i].Name] = &list[i]
+	}
+
+	s.mu.Lock()
+	s.roles = roles
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return roles, nil
+}
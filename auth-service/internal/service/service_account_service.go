@@ -0,0 +1,300 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyPrefix        = "cck_"
+	apiKeyDisplayLength = 12
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	ErrInvalidClient          = errors.New("invalid client credentials")
+	ErrNameInUse              = errors.New("service account name already in use")
+	ErrUnknownScope           = errors.New("unknown scope")
+	ErrInvalidDepartment      = errors.New("invalid department")
+	ErrServiceAccountNotFound = repository.ErrServiceAccountNotFound
+	ErrAPIKeyNotFound         = repository.ErrAPIKeyNotFound
+)
+
+type ServiceAccountService struct {
+	repo      *repository.ServiceAccountRepository
+	userRepo  *repository.UserRepository
+	auditRepo *repository.AuditRepository
+	roles     *RoleService
+}
+
+func NewServiceAccountService(repo *repository.ServiceAccountRepository, userRepo *repository.UserRepository, auditRepo *repository.AuditRepository, roles *RoleService) *ServiceAccountService {
+	return &ServiceAccountService{
+		repo:      repo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		roles:     roles,
+	}
+}
+
+func (s *ServiceAccountService) CreateServiceAccount(actorID uuid.UUID, req *model.CreateServiceAccountRequest, ip string) (*model.ServiceAccount, error) {
+	if _, err := authorizeActor(s.userRepo, s.roles, actorID, model.PermServiceAccountManage); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	exists, err := s.repo.NameExists(name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrNameInUse
+	}
+
+	account := &model.ServiceAccount{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: req.Description,
+		CreatedBy:   &actorID,
+		CreatedAt:   time.Now(),
+		Keys:        []model.APIKey{},
+	}
+	if req.Department != "" {
+		exists, err := s.roles.DepartmentExists(req.Department)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrInvalidDepartment
+		}
+		account.Department = &req.Department
+	}
+
+	if err := s.repo.Create(account); err != nil {
+		return nil, err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:     model.AuditServiceAccountCreated,
+		ActorID:   &actorID,
+		IPAddress: ip,
+		Details: map[string]interface{}{
+			"service_account_id": account.ID,
+			"name":               account.Name,
+			"department":         account.Department,
+		},
+	})
+	return account, nil
+}
+
+func (s *ServiceAccountService) ListServiceAccounts(actorID uuid.UUID) ([]model.ServiceAccount, error) {
+	if _, err := authorizeActor(s.userRepo, s.roles, actorID, model.PermServiceAccountManage); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.FindKeys(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[uuid.UUID]int, len(accounts))
+	for i := range accounts {
+		index[accounts[i].ID] = i
+	}
+	for _, key := range keys {
+		if i, ok := index[key.ServiceAccountID]; ok {
+			accounts[i].Keys = append(accounts[i].Keys, key)
+		}
+	}
+
+	return accounts, nil
+}
+
+// CreateAPIKey issues a new key for a service account. Every scope must be
+// an existing permission name; the plaintext key is returned only here.
+func (s *ServiceAccountService) CreateAPIKey(actorID, accountID uuid.UUID, req *model.CreateAPIKeyRequest, ip string) (*model.CreateAPIKeyResponse, error) {
+	if _, err := authorizeActor(s.userRepo, s.roles, actorID, model.PermServiceAccountManage); err != nil {
+		return nil, err
+	}
+
+	account, err := s.repo.FindByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if seen[scope] {
+			continue
+		}
+		exists, err := s.roles.PermissionExists(scope)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	raw := apiKeyPrefix + secret
+
+	now := time.Now()
+	key := &model.APIKey{
+		ID:               uuid.New(),
+		ServiceAccountID: account.ID,
+		Name:             strings.TrimSpace(req.Name),
+		Prefix:           raw[:apiKeyDisplayLength],
+		KeyHash:          hashToken(raw),
+		Scopes:           scopes,
+		CreatedBy:        &actorID,
+		CreatedAt:        now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	tx, err := s.repo.BeginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.CreateKeyInTransaction(tx, key); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:     model.AuditAPIKeyCreated,
+		ActorID:   &actorID,
+		IPAddress: ip,
+		Details: map[string]interface{}{
+			"service_account_id": account.ID,
+			"api_key_id":         key.ID,
+			"prefix":             key.Prefix,
+			"scopes":             key.Scopes,
+		},
+	})
+
+	return &model.CreateAPIKeyResponse{Key: raw, APIKey: key}, nil
+}
+
+func (s *ServiceAccountService) ListAPIKeys(actorID, accountID uuid.UUID) ([]model.APIKey, error) {
+	if _, err := authorizeActor(s.userRepo, s.roles, actorID, model.PermServiceAccountManage); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindByID(accountID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindKeys(&accountID)
+}
+
+func (s *ServiceAccountService) RevokeAPIKey(actorID, accountID, keyID uuid.UUID, ip string) error {
+	if _, err := authorizeActor(s.userRepo, s.roles, actorID, model.PermServiceAccountManage); err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.RevokeKey(accountID, keyID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:     model.AuditAPIKeyRevoked,
+		ActorID:   &actorID,
+		IPAddress: ip,
+		Details: map[string]interface{}{
+			"service_account_id": accountID,
+			"api_key_id":         keyID,
+		},
+	})
+	return nil
+}
+
+// ValidateAPIKey resolves an X-API-Key into the same identity headers a user
+// token produces. The service account stands in for the user and the key's
+// scopes are its permissions.
+func (s *ServiceAccountService) ValidateAPIKey(rawKey, ip string) (*model.ValidateResponse, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return &model.ValidateResponse{Valid: false}, nil
+	}
+
+	key, err := s.repo.FindKeyByHash(hashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return &model.ValidateResponse{Valid: false}, nil
+		}
+		return nil, err
+	}
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+		return &model.ValidateResponse{Valid: false}, nil
+	}
+
+	account, err := s.repo.FindByID(key.ServiceAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.TouchKey(key.ID, ip, apiKeyTouchInterval); err != nil {
+		log.Printf("api key %s: record last use: %v", key.Prefix, err)
+	}
+
+	department := ""
+	if account.Department != nil {
+		department = *account.Department
+	}
+
+	return &model.ValidateResponse{
+		Valid:       true,
+		UserID:      account.ID.String(),
+		Role:        model.ServiceAccountRole,
+		Department:  department,
+		Name:        account.Name,
+		Permissions: key.Scopes,
+	}, nil
+}
+
+// AuthenticateClient checks OAuth-style client credentials: the client id is
+// the service account id and the secret is one of its API keys, which must
+// carry scope.
+func (s *ServiceAccountService) AuthenticateClient(clientID, clientSecret, scope, ip string) error {
+	identity, err := s.ValidateAPIKey(clientSecret, ip)
+	if err != nil {
+		return err
+	}
+	if !identity.Valid || identity.UserID != clientID {
+		return ErrInvalidClient
+	}
+
+	for _, granted := range identity.Permissions {
+		if granted == scope {
+			return nil
+		}
+	}
+	return ErrPermissionDenied
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// base32 of the RFC 6238 SHA1 test key "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; ours are their last six digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	key := []byte("12345678901234567890")
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / totpPeriod
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfc6238Secret, totpCode(key, current), current, true},
+		{"previous step", rfc6238Secret, totpCode(key, current-1), current - 1, true},
+		{"next step", rfc6238Secret, totpCode(key, current+1), current + 1, true},
+		{"two steps behind", rfc6238Secret, totpCode(key, current-2), 0, false},
+		{"two steps ahead", rfc6238Secret, totpCode(key, current+2), 0, false},
+		{"lower-case secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", totpCode(key, current), current, true},
+		{"wrong code", rfc6238Secret, "000000", 0, false},
+		{"empty code", rfc6238Secret, "", 0, false},
+		{"invalid secret", "not base32!", totpCode(key, current), 0, false},
+	}
+
+	for _, tt := range tests {
+		step, ok := verifyTOTP(tt.secret, tt.code, now)
+		if ok != tt.wantOK || step != tt.wantStep {
+			t.Errorf("%s: verifyTOTP = (%d, %v), want (%d, %v)", tt.name, step, ok, tt.wantStep, tt.wantOK)
+		}
+	}
+}
+
+func TestIsTOTPCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"123456", true},
+		{"000000", true},
+		{"12345", false},
+		{"1234567", false},
+		{"12a456", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isTOTPCode(tt.code); got != tt.want {
+			t.Errorf("isTOTPCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"errors"
+
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+var (
+	ErrPermissionDenied = errors.New("insufficient permissions")
+	ErrSelfModification = errors.New("cannot change your own account this way")
+)
+
+type UserAdminService struct {
+	userRepo       *repository.UserRepository
+	tokenRepo      *repository.TokenRepository
+	auditRepo      *repository.AuditRepository
+	roles          *RoleService
+	accountService *AccountService
+}
+
+func NewUserAdminService(userRepo *repository.UserRepository, tokenRepo *repository.TokenRepository, auditRepo *repository.AuditRepository, roles *RoleService, accountService *AccountService) *UserAdminService {
+	return &UserAdminService{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		auditRepo:      auditRepo,
+		roles:          roles,
+		accountService: accountService,
+	}
+}
+
+func (s *UserAdminService) ListUsers(actorID uuid.UUID, filter model.UserFilter) (*model.UserListResponse, error) {
+	if _, err := s.authorize(actorID, model.PermUserRead); err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.PageSize = normalizePage(filter.Page, filter.PageSize)
+	users, total, err := s.userRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserListResponse{
+		Users:    users,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+func (s *UserAdminService) GetUser(actorID, userID uuid.UUID) (*model.User, error) {
+	if _, err := s.authorize(actorID, model.PermUserRead); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Permissions, err = s.roles.Permissions(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangeRole moves a user to another role. The department defaults to the
+// role's own department; existing sessions are signed out so the user's
+// next tokens carry the new role.
+func (s *UserAdminService) ChangeRole(actorID, userID uuid.UUID, req *model.ChangeRoleRequest, ip string) (*model.User, error) {
+	if _, err := s.authorize(actorID, model.PermUserManage); err != nil {
+		return nil, err
+	}
+	if actorID == userID {
+		return nil, ErrSelfModification
+	}
+
+	role, err := s.roles.Get(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	department := role.Department
+	if req.Department != "" {
+		department = &req.Department
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	oldRole, oldDepartment := user.Role, user.Department
+
+	if err := s.userRepo.UpdateRole(userID, role.Name, department); err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.RevokeAllRefreshTokens(userID); err != nil {
+		return nil, err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:     model.AuditRoleChanged,
+		UserID:    &userID,
+		ActorID:   &actorID,
+		Email:     user.Email,
+		IPAddress: ip,
+		Details: map[string]interface{}{
+			"old_role":       oldRole,
+			"old_department": oldDepartment,
+			"new_role":       role.Name,
+			"new_department": department,
+		},
+	})
+
+	return s.GetUser(actorID, userID)
+}
+
+// SetActive deactivates or reactivates an account. Deactivation takes effect
+// immediately because /validate checks the flag on every request.
+func (s *UserAdminService) SetActive(actorID, userID uuid.UUID, active bool, reason, ip string) error {
+	if _, err := s.authorize(actorID, model.PermUserManage); err != nil {
+		return err
+	}
+	if actorID == userID {
+		return ErrSelfModification
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.SetActive(userID, active); err != nil {
+		return err
+	}
+
+	event := model.AuditUserActivated
+	if !active {
+		event = model.AuditUserDeactivated
+		if err := s.tokenRepo.RevokeAllRefreshTokens(userID); err != nil {
+			return err
+		}
+	}
+
+	var details map[string]interface{}
+	if reason != "" {
+		details = map[string]interface{}{"reason": reason}
+	}
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:     event,
+		UserID:    &userID,
+		ActorID:   &actorID,
+		Email:     user.Email,
+		IPAddress: ip,
+		Details:   details,
+	})
+	return nil
+}
+
+// ForcePasswordReset replaces the password with an unusable one, signs out
+// every session and emails the user a reset link.
+func (s *UserAdminService) ForcePasswordReset(actorID, userID uuid.UUID, ip string) error {
+	if _, err := s.authorize(actorID, model.PermUserManage); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	random, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, string(hashed)); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.RevokeAllRefreshTokens(userID); err != nil {
+		return err
+	}
+	if err := s.accountService.ForcePasswordReset(user); err != nil {
+		return err
+	}
+
+	writeAudit(s.auditRepo, &model.AuditEvent{
+		Event:     model.AuditPasswordReset,
+		UserID:    &userID,
+		ActorID:   &actorID,
+		Email:     user.Email,
+		IPAddress: ip,
+	})
+	return nil
+}
+
+func (s *UserAdminService) ListAuditEvents(actorID uuid.UUID, filter model.AuditFilter) (*model.AuditListResponse, error) {
+	if _, err := s.authorize(actorID, model.PermAuditRead); err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.PageSize = normalizePage(filter.Page, filter.PageSize)
+	events, total, err := s.auditRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuditListResponse{
+		Events:   events,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+func (s *UserAdminService) authorize(actorID uuid.UUID, permission string) (*model.User, error) {
+	return authorizeActor(s.userRepo, s.roles, actorID, permission)
+}
+
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultUserPageSize
+	}
+	if pageSize > maxUserPageSize {
+		pageSize = maxUserPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,93 @@
+// Package userdata talks to the other CityConnect services about a single
+// user's data: collecting it for an export and anonymising it before the
+// account is deleted.
+package userdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"auth-service/config"
+
+	"github.com/google/uuid"
+)
+
+const requestTimeout = 10 * time.Second
+
+// ReportData is report-service's part of an export. The contents are passed
+// through as the service returned them.
+type ReportData struct {
+	Reports  json.RawMessage `json:"reports"`
+	Votes    json.RawMessage `json:"votes"`
+	Comments json.RawMessage `json:"comments"`
+}
+
+type NotificationData struct {
+	Notifications json.RawMessage `json:"notifications"`
+}
+
+type Client struct {
+	cfg    config.ServicesConfig
+	client *http.Client
+}
+
+func NewClient(cfg config.ServicesConfig) *Client {
+	cfg.ReportURL = strings.TrimRight(cfg.ReportURL, "/")
+	cfg.NotificationURL = strings.TrimRight(cfg.NotificationURL, "/")
+
+	return &Client{
+		cfg:    cfg,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (c *Client) Reports(userID uuid.UUID) (*ReportData, error) {
+	var data ReportData
+	if err := c.do(http.MethodGet, c.cfg.ReportURL+"/internal/users/"+userID.String()+"/export", &data); err != nil {
+		return nil, fmt.Errorf("report-service export: %w", err)
+	}
+	return &data, nil
+}
+
+func (c *Client) Notifications(userID uuid.UUID) (*NotificationData, error) {
+	var data NotificationData
+	if err := c.do(http.MethodGet, c.cfg.NotificationURL+"/internal/users/"+userID.String()+"/export", &data); err != nil {
+		return nil, fmt.Errorf("notification-service export: %w", err)
+	}
+	return &data, nil
+}
+
+// AnonymiseReports detaches the user's reports from their account and
+// returns how many were changed. It is safe to repeat.
+func (c *Client) AnonymiseReports(userID uuid.UUID) (int64, error) {
+	var result struct {
+		AnonymisedReports int64 `json:"anonymised_reports"`
+	}
+	if err := c.do(http.MethodPost, c.cfg.ReportURL+"/internal/users/"+userID.String()+"/anonymise", &result); err != nil {
+		return 0, fmt.Errorf("report-service anonymise: %w", err)
+	}
+	return result.AnonymisedReports, nil
+}
+
+func (c *Client) do(method, url string, out interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Internal-Token", c.cfg.InternalToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
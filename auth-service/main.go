@@ -4,11 +4,15 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
 	"auth-service/config"
 	"auth-service/internal/handler"
+	"auth-service/internal/mailer"
+	"auth-service/internal/oidc"
 	"auth-service/internal/repository"
 	"auth-service/internal/service"
+	"auth-service/internal/userdata"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
@@ -40,17 +44,129 @@ func main() {
 	log.Println("Connected to database")
 
 	userRepo := repository.NewUserRepository(db)
-	authService := service.NewAuthService(userRepo, cfg.JWT)
-	authHandler := handler.NewAuthHandler(authService)
+	tokenRepo := repository.NewTokenRepository(db)
+	inviteRepo := repository.NewInvitationRepository(db)
+	acctRepo := repository.NewAccountTokenRepository(db)
+	attemptRepo := repository.NewLoginAttemptRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	serviceAccountRepo := repository.NewServiceAccountRepository(db)
+	oidcRepo := repository.NewOIDCRepository(db)
+
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	keyManager, err := service.NewKeyManager(cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+
+	roleService := service.NewRoleService(roleRepo)
+	loginGuard := service.NewLoginGuard(attemptRepo, userRepo, auditRepo, roleService, cfg.Login)
+	mfaService := service.NewMFAService(mfaRepo, userRepo, acctRepo, auditRepo, roleService, cfg.MFA)
+	authService := service.NewAuthService(userRepo, tokenRepo, inviteRepo, roleService, keyManager, loginGuard, mfaService, cfg.JWT)
+	invitationService := service.NewInvitationService(inviteRepo, userRepo, roleService)
+	accountService := service.NewAccountService(userRepo, tokenRepo, acctRepo, auditRepo, mail, cfg.Mail.AppURL)
+	profileService := service.NewProfileService(userRepo, tokenRepo, auditRepo, authService, accountService, loginGuard)
+	userAdminService := service.NewUserAdminService(userRepo, tokenRepo, auditRepo, roleService, accountService)
+	serviceAccountService := service.NewServiceAccountService(serviceAccountRepo, userRepo, auditRepo, roleService)
+	privacyService := service.NewPrivacyService(userRepo, tokenRepo, oidcRepo, auditRepo, authService, loginGuard, userdata.NewClient(cfg.Services))
+	oidcService := service.NewOIDCService(oidc.NewProvider(cfg.OIDC), oidcRepo, userRepo, acctRepo, auditRepo, roleService, authService, cfg.OIDC)
+	authHandler := handler.NewAuthHandler(authService, accountService, serviceAccountService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	invitationHandler := handler.NewInvitationHandler(invitationService, authService)
+	profileHandler := handler.NewProfileHandler(authService, profileService)
+	adminHandler := handler.NewAdminHandler(authService, loginGuard, userAdminService)
+	mfaHandler := handler.NewMFAHandler(mfaService, authService)
+	serviceAccountHandler := handler.NewServiceAccountHandler(authService, serviceAccountService)
+	oidcHandler := handler.NewOIDCHandler(oidcService)
+	privacyHandler := handler.NewPrivacyHandler(authService, privacyService)
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			deleted, err := authService.PurgeExpiredTokens()
+			if err != nil {
+				log.Printf("token cleanup: %v", err)
+			} else if deleted > 0 {
+				log.Printf("token cleanup: removed %d expired tokens", deleted)
+			}
+
+			deleted, err = accountService.PurgeExpiredTokens()
+			if err != nil {
+				log.Printf("account token cleanup: %v", err)
+			} else if deleted > 0 {
+				log.Printf("account token cleanup: removed %d expired tokens", deleted)
+			}
+
+			if _, err := loginGuard.PurgeAttempts(); err != nil {
+				log.Printf("login attempt cleanup: %v", err)
+			}
+
+			if _, err := oidcService.PurgeExpiredStates(); err != nil {
+				log.Printf("oidc state cleanup: %v", err)
+			}
+		}
+	}()
 
 	r := gin.Default()
+	// Login throttling keys on the client IP; only trust the header nginx
+	// overwrites, not an X-Forwarded-For the client could send itself.
+	r.RemoteIPHeaders = []string{"X-Real-IP"}
 
 	r.GET("/health", authHandler.Health)
+	r.GET("/.well-known/jwks.json", authHandler.JWKS)
 
 	r.POST("/register", authHandler.Register)
 	r.POST("/login", authHandler.Login)
+	r.POST("/login/mfa", authHandler.LoginMFA)
+	r.POST("/login/mfa/enroll", authHandler.LoginMFAEnroll)
+	r.GET("/oidc/login", oidcHandler.Login)
+	r.GET("/oidc/callback", oidcHandler.Callback)
+	r.POST("/oidc/token", oidcHandler.Token)
+	r.POST("/refresh", authHandler.Refresh)
+	r.POST("/logout", authHandler.Logout)
 	r.GET("/validate", authHandler.Validate)
+	r.POST("/introspect", authHandler.Introspect)
 	r.GET("/me", authHandler.Me)
+	r.PATCH("/me", profileHandler.UpdateProfile)
+	r.DELETE("/me", privacyHandler.DeleteAccount)
+	r.GET("/me/export", privacyHandler.Export)
+	r.POST("/me/password", profileHandler.ChangePassword)
+	r.GET("/me/sessions", profileHandler.ListSessions)
+	r.DELETE("/me/sessions/:id", profileHandler.RevokeSession)
+	r.GET("/roles", authHandler.ListRoles)
+
+	r.POST("/password/forgot", accountHandler.ForgotPassword)
+	r.POST("/password/reset", accountHandler.ResetPassword)
+	r.POST("/email/verify", accountHandler.VerifyEmail)
+
+	r.GET("/mfa", mfaHandler.Status)
+	r.POST("/mfa/enroll", mfaHandler.Enroll)
+	r.POST("/mfa/activate", mfaHandler.Activate)
+	r.POST("/mfa/disable", mfaHandler.Disable)
+	r.POST("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+
+	r.POST("/invitations", invitationHandler.CreateInvitation)
+	r.GET("/invitations", invitationHandler.ListInvitations)
+
+	r.GET("/users", adminHandler.ListUsers)
+	r.GET("/users/:id", adminHandler.GetUser)
+	r.PATCH("/users/:id/role", adminHandler.ChangeRole)
+	r.POST("/users/:id/deactivate", adminHandler.DeactivateUser)
+	r.POST("/users/:id/activate", adminHandler.ActivateUser)
+	r.POST("/users/:id/password-reset", adminHandler.ForcePasswordReset)
+	r.POST("/users/:id/unlock", adminHandler.UnlockUser)
+	r.GET("/audit", adminHandler.ListAuditEvents)
+
+	r.POST("/service-accounts", serviceAccountHandler.CreateServiceAccount)
+	r.GET("/service-accounts", serviceAccountHandler.ListServiceAccounts)
+	r.POST("/service-accounts/:id/keys", serviceAccountHandler.CreateAPIKey)
+	r.GET("/service-accounts/:id/keys", serviceAccountHandler.ListAPIKeys)
+	r.DELETE("/service-accounts/:id/keys/:keyId", serviceAccountHandler.RevokeAPIKey)
 
 	addr := fmt.Sprintf(":%s", cfg.Server.Port)
 	log.Printf("Auth service starting on %s", addr)
@@ -6,6 +6,38 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
+CREATE EXTENSION IF NOT EXISTS "unaccent";
+
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+
+-- =====================
+-- DEPARTMENTS, ROLES & PERMISSIONS
+-- =====================
+-- Adding a department is data only: insert the department, its admin and
+-- officer roles and their permissions.
+CREATE TABLE departments (
+    code VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255),
+    department VARCHAR(100) REFERENCES departments (code),
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description VARCHAR(255)
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
 -- =====================
 -- USERS TABLE
 -- =====================
@@ -14,15 +46,14 @@ CREATE TABLE users (
     email VARCHAR(255) UNIQUE NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
     name VARCHAR(255) NOT NULL,
-    role VARCHAR(50) NOT NULL CHECK (
-        role IN (
-            'warga',
-            'admin_kebersihan',
-            'admin_kesehatan',
-            'admin_infrastruktur'
-        )
-    ),
+    role VARCHAR(50) NOT NULL REFERENCES roles (name),
     department VARCHAR(100),
+    email_verified_at TIMESTAMP,
+    pending_email VARCHAR(255),
+    failed_login_count INTEGER DEFAULT 0,
+    locked_until TIMESTAMP,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    deactivated_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT NOW()
 );
 
@@ -31,13 +62,230 @@ CREATE INDEX idx_users_email ON users (email);
 
 CREATE INDEX idx_users_role ON users (role);
 
+-- =====================
+-- SESSIONS TABLE
+-- =====================
+-- One row per login (device). Access tokens carry the session id as "sid",
+-- so revoking a session cuts off its access tokens at /validate.
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_agent VARCHAR(512),
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP DEFAULT NOW(),
+    last_seen_at TIMESTAMP DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user ON sessions (user_id);
+
+-- =====================
+-- REFRESH TOKENS TABLE
+-- =====================
+-- Rotating refresh tokens; only the SHA-256 hash of the token is stored.
+-- Tokens issued from the same login share a family_id (the session id) so
+-- reuse of a rotated token can revoke the whole chain.
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    family_id UUID NOT NULL REFERENCES sessions (id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens (user_id);
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens (family_id);
+
+-- =====================
+-- REVOKED ACCESS TOKENS TABLE
+-- =====================
+-- Denylist of access token IDs (jti) until their natural expiry
+CREATE TABLE revoked_tokens (
+    jti UUID PRIMARY KEY,
+    user_id UUID REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_revoked_tokens_expires ON revoked_tokens (expires_at);
+
+-- =====================
+-- INVITATIONS TABLE
+-- =====================
+-- Single-use invite codes for provisioning admin accounts.
+-- Only the SHA-256 hash of the code is stored.
+CREATE TABLE invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    role VARCHAR(50) NOT NULL REFERENCES roles (name),
+    department VARCHAR(100),
+    created_by UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    used_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_invitations_created_by ON invitations (created_by);
+
+-- =====================
+-- ACCOUNT TOKENS TABLE
+-- =====================
+-- One-time tokens for password reset, email verification, email changes,
+-- MFA login challenges and OIDC login hand-off (hash only)
+CREATE TABLE account_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL CHECK (
+        purpose IN (
+            'password_reset',
+            'email_verification',
+            'email_change',
+            'mfa_challenge',
+            'oidc_login'
+        )
+    ),
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_account_tokens_user ON account_tokens (user_id, purpose);
+
+-- =====================
+-- MFA TABLES
+-- =====================
+-- TOTP secret per user; enabled_at stays NULL until the first code is confirmed
+CREATE TABLE user_mfa (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user ON mfa_recovery_codes (user_id);
+
+-- =====================
+-- OIDC FEDERATED LOGIN
+-- =====================
+-- Pending authorization requests: state (hashed), nonce and PKCE verifier
+CREATE TABLE oidc_login_states (
+    state_hash VARCHAR(64) PRIMARY KEY,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Links an external identity (issuer + subject) to a local user
+CREATE TABLE user_identities (
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email VARCHAR(255),
+    created_at TIMESTAMP DEFAULT NOW(),
+    last_login_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities (user_id);
+
+-- =====================
+-- LOGIN ATTEMPTS TABLE
+-- =====================
+-- Every /login attempt, used for progressive delays and per-IP throttling
+CREATE TABLE login_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL,
+    success BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_attempts_email ON login_attempts (email, created_at DESC);
+
+CREATE INDEX idx_login_attempts_ip ON login_attempts (ip_address, created_at DESC);
+
+-- =====================
+-- AUTH AUDIT LOG TABLE
+-- =====================
+CREATE TABLE auth_audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    event VARCHAR(50) NOT NULL,
+    user_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    actor_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    email VARCHAR(255),
+    ip_address VARCHAR(64),
+    details JSONB,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_audit_user ON auth_audit_log (user_id, created_at DESC);
+
+CREATE INDEX idx_auth_audit_event ON auth_audit_log (event, created_at DESC);
+
+-- =====================
+-- SERVICE ACCOUNTS & API KEYS
+-- =====================
+-- Non-human callers (other city systems). A service account acts within an
+-- optional department; each of its keys carries its own set of permissions.
+CREATE TABLE service_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    department VARCHAR(100) REFERENCES departments (code),
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Only the SHA-256 hash of a key is stored; prefix is kept for display
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    service_account_id UUID NOT NULL REFERENCES service_accounts (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    last_used_ip VARCHAR(64),
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_account ON api_keys (service_account_id);
+
+CREATE TABLE api_key_scopes (
+    api_key_id UUID NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (api_key_id, permission)
+);
+
 -- =====================
 -- CATEGORIES TABLE
 -- =====================
+-- Service-level targets in hours from filing: acknowledgement is the
+-- department's first decision on a pending report, resolution is completing
+-- or rejecting it. NULL means no target.
 CREATE TABLE categories (
     id SERIAL PRIMARY KEY,
     name VARCHAR(100) NOT NULL,
-    department VARCHAR(100) NOT NULL
+    department VARCHAR(100) NOT NULL REFERENCES departments (code),
+    ack_target_hours INTEGER CHECK (ack_target_hours > 0),
+    resolution_target_hours INTEGER CHECK (resolution_target_hours > 0)
 );
 
 -- Index for department filtering
@@ -46,6 +294,18 @@ CREATE INDEX idx_categories_department ON categories (department);
 -- =====================
 -- REPORTS TABLE
 -- =====================
+-- Full-text search: the Indonesian Snowball stemmer, after folding accents
+-- so "kafe" also finds "café"
+CREATE TEXT SEARCH CONFIGURATION cityconnect (COPY = indonesian);
+
+ALTER TEXT SEARCH CONFIGURATION cityconnect
+ALTER MAPPING FOR hword,
+hword_part,
+word
+WITH
+    unaccent,
+    indonesian_stem;
+
 CREATE TABLE reports (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
     title VARCHAR(255) NOT NULL,
@@ -53,7 +313,7 @@ CREATE TABLE reports (
     category_id INTEGER REFERENCES categories (id),
     location_lat DECIMAL(10, 8),
     location_lng DECIMAL(11, 8),
-    photo_url TEXT,
+    photo_url TEXT, -- Legacy client-hosted photo; new reports use report_attachments
     privacy_level VARCHAR(20) NOT NULL CHECK (
         privacy_level IN (
             'public',
@@ -72,9 +332,41 @@ CREATE TABLE reports (
             'rejected'
         )
     ),
+    status_reason TEXT, -- Why the report was last rejected or reopened
     vote_score INTEGER DEFAULT 0, -- Net score (upvotes - downvotes)
     created_at TIMESTAMP DEFAULT NOW(),
-    updated_at TIMESTAMP DEFAULT NOW()
+    updated_at TIMESTAMP DEFAULT NOW(),
+    duplicate_of UUID REFERENCES reports (id), -- Canonical report this one was merged into
+    merged_at TIMESTAMP,
+    assignee_id UUID REFERENCES users (id) ON DELETE SET NULL, -- Officer handling the report
+    assigned_at TIMESTAMP,
+    acknowledged_at TIMESTAMP, -- First move out of pending
+    closed_at TIMESTAMP, -- Last completion or rejection; NULL while open
+    -- SLA deadlines and state, maintained by report-service's SLA job
+    ack_due_at TIMESTAMP,
+    resolution_due_at TIMESTAMP,
+    sla_due_at TIMESTAMP, -- The deadline currently running; NULL once closed
+    sla_state VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (
+        sla_state IN (
+            'none',
+            'on_track',
+            'at_risk',
+            'breached',
+            'met'
+        )
+    ),
+    ack_breached_at TIMESTAMP, -- Set once report.sla.breached was emitted
+    resolution_breached_at TIMESTAMP,
+    -- Title matches rank above description matches
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(
+            to_tsvector('cityconnect', title),
+            'A'
+        ) || setweight(
+            to_tsvector('cityconnect', description),
+            'B'
+        )
+    ) STORED
 );
 
 -- Indexes for common queries
@@ -82,11 +374,129 @@ CREATE INDEX idx_reports_category ON reports (category_id);
 
 CREATE INDEX idx_reports_status ON reports (status);
 
-CREATE INDEX idx_reports_reporter ON reports (reporter_id);
+-- Listings page with keyset cursors, so the indexes carry the full sort key
+-- including the id tiebreaker
+CREATE INDEX idx_reports_reporter ON reports (reporter_id, created_at DESC, id DESC);
 
-CREATE INDEX idx_reports_created ON reports (created_at DESC);
+CREATE INDEX idx_reports_created ON reports (created_at DESC, id DESC);
 
-CREATE INDEX idx_reports_vote_score ON reports (vote_score DESC);
+CREATE INDEX idx_reports_vote_score ON reports (vote_score DESC, created_at DESC, id DESC);
+
+CREATE INDEX idx_reports_search ON reports USING GIN (search_vector);
+
+-- Nearby and bounding box queries only cover the public feed
+CREATE INDEX idx_reports_location ON reports (location_lat, location_lng)
+WHERE
+    privacy_level = 'public';
+
+CREATE INDEX idx_reports_duplicate_of ON reports (duplicate_of)
+WHERE
+    duplicate_of IS NOT NULL;
+
+CREATE INDEX idx_reports_assignee ON reports (assignee_id, created_at DESC, id DESC)
+WHERE
+    assignee_id IS NOT NULL;
+
+CREATE INDEX idx_reports_sla_due ON reports (sla_due_at, id)
+WHERE
+    sla_due_at IS NOT NULL;
+
+CREATE INDEX idx_reports_sla_state ON reports (sla_state);
+
+-- Deadlines not escalated yet, scanned by the SLA job on every run
+CREATE INDEX idx_reports_ack_unescalated ON reports (ack_due_at)
+WHERE
+    ack_breached_at IS NULL;
+
+CREATE INDEX idx_reports_resolution_unescalated ON reports (resolution_due_at)
+WHERE
+    resolution_breached_at IS NULL;
+
+-- =====================
+-- REPORT STATUS HISTORY TABLE
+-- =====================
+-- One row per status change. actor_id has no foreign key because service
+-- accounts can change statuses too; actor_name keeps who it was readable.
+CREATE TABLE report_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    report_id UUID NOT NULL REFERENCES reports (id) ON DELETE CASCADE,
+    actor_id UUID,
+    actor_name VARCHAR(255),
+    old_status VARCHAR(50) NOT NULL,
+    new_status VARCHAR(50) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_status_history_report ON report_status_history (report_id, created_at);
+
+-- =====================
+-- REPORT ASSIGNMENTS TABLE
+-- =====================
+-- One row per assignment or reassignment of a report to an officer. As in
+-- report_status_history, actor_id has no foreign key.
+CREATE TABLE report_assignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    report_id UUID NOT NULL REFERENCES reports (id) ON DELETE CASCADE,
+    assignee_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    previous_assignee_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    actor_id UUID,
+    actor_name VARCHAR(255),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_assignments_report ON report_assignments (report_id, created_at);
+
+-- =====================
+-- REPORT COMMENTS TABLE
+-- =====================
+-- author_id is NULL for comments by the reporter of an anonymous report, and
+-- once the author's account is deleted
+CREATE TABLE report_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    report_id UUID NOT NULL REFERENCES reports (id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES report_comments (id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    is_reporter BOOLEAN NOT NULL DEFAULT FALSE,
+    is_official BOOLEAN NOT NULL DEFAULT FALSE, -- Written by staff of the report's department
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_comments_report ON report_comments (report_id, created_at);
+
+CREATE INDEX idx_report_comments_author ON report_comments (author_id);
+
+-- =====================
+-- REPORT ATTACHMENTS TABLE
+-- =====================
+-- Uploaded photos and documents. A citizen's upload belongs to no report
+-- until a report is filed with its ID; the blobs live in the configured
+-- BlobStore.
+CREATE TABLE report_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    report_id UUID REFERENCES reports (id) ON DELETE CASCADE,
+    uploader_id UUID REFERENCES users (id) ON DELETE SET NULL, -- NULL once attached to an anonymous report
+    role VARCHAR(20) NOT NULL DEFAULT 'evidence' CHECK (
+        role IN (
+            'evidence',
+            'progress',
+            'resolution'
+        )
+    ),
+    storage_key TEXT NOT NULL,
+    thumbnail_key TEXT, -- NULL for documents
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER, -- NULL for documents
+    height INTEGER,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_attachments_report ON report_attachments (report_id, created_at);
+
+CREATE INDEX idx_report_attachments_uploader ON report_attachments (uploader_id);
 
 -- =====================
 -- REPORT VOTES TABLE
@@ -159,40 +569,231 @@ CREATE INDEX idx_processed_messages_time ON processed_messages (processed_at);
 -- Clean up old processed messages (older than 7 days) - run periodically
 -- DELETE FROM processed_messages WHERE processed_at < NOW() - INTERVAL '7 days';
 
+-- =====================
+-- SEED DATA - Departments, Roles & Permissions
+-- =====================
+INSERT INTO
+    departments (code, name)
+VALUES (
+        'kebersihan',
+        'Dinas Kebersihan'
+    ),
+    (
+        'kesehatan',
+        'Dinas Kesehatan'
+    ),
+    (
+        'infrastruktur',
+        'Dinas Infrastruktur'
+    );
+
+INSERT INTO
+    permissions (name, description)
+VALUES (
+        'report.read.department',
+        'View every report filed under the own department'
+    ),
+    (
+        'report.status.update',
+        'Change the status of reports in the own department'
+    ),
+    (
+        'report.assign',
+        'Assign reports in the own department to its officers'
+    ),
+    (
+        'user.invite',
+        'Issue invite codes for the own role and the own department''s officers'
+    ),
+    (
+        'user.unlock',
+        'Lift a login lockout'
+    ),
+    (
+        'user.read',
+        'List and search all user accounts'
+    ),
+    (
+        'user.manage',
+        'Change roles, deactivate accounts and force password resets'
+    ),
+    (
+        'audit.read',
+        'Read the auth audit trail'
+    ),
+    (
+        'service_account.manage',
+        'Create service accounts and issue or revoke their API keys'
+    ),
+    (
+        'token.introspect',
+        'Check user access tokens via /introspect (API key scope)'
+    ),
+    (
+        'sla.manage',
+        'Set the SLA targets of report categories'
+    );
+
+INSERT INTO
+    roles (
+        name,
+        description,
+        department,
+        is_admin
+    )
+VALUES (
+        'warga',
+        'Warga',
+        NULL,
+        FALSE
+    ),
+    (
+        'admin_kebersihan',
+        'Admin Dinas Kebersihan',
+        'kebersihan',
+        TRUE
+    ),
+    (
+        'admin_kesehatan',
+        'Admin Dinas Kesehatan',
+        'kesehatan',
+        TRUE
+    ),
+    (
+        'admin_infrastruktur',
+        'Admin Dinas Infrastruktur',
+        'infrastruktur',
+        TRUE
+    ),
+    (
+        'petugas_kebersihan',
+        'Petugas Dinas Kebersihan',
+        'kebersihan',
+        FALSE
+    ),
+    (
+        'petugas_kesehatan',
+        'Petugas Dinas Kesehatan',
+        'kesehatan',
+        FALSE
+    ),
+    (
+        'petugas_infrastruktur',
+        'Petugas Dinas Infrastruktur',
+        'infrastruktur',
+        FALSE
+    ),
+    (
+        'superadmin',
+        'Super Admin Kota',
+        NULL,
+        TRUE
+    );
+
+INSERT INTO
+    role_permissions (role, permission)
+SELECT r.name, p.name
+FROM roles r
+    CROSS JOIN permissions p
+WHERE
+    r.is_admin
+    AND r.department IS NOT NULL
+    AND p.name IN (
+        'report.read.department',
+        'report.status.update',
+        'report.assign',
+        'user.invite',
+        'user.unlock'
+    );
+
+-- Officers work the reports of their department but cannot assign them or
+-- manage accounts
+INSERT INTO
+    role_permissions (role, permission)
+SELECT r.name, p.name
+FROM roles r
+    CROSS JOIN permissions p
+WHERE
+    NOT r.is_admin
+    AND r.department IS NOT NULL
+    AND p.name IN (
+        'report.read.department',
+        'report.status.update'
+    );
+
+INSERT INTO
+    role_permissions (role, permission)
+VALUES ('superadmin', 'user.invite'),
+    ('superadmin', 'user.unlock'),
+    ('superadmin', 'user.read'),
+    ('superadmin', 'user.manage'),
+    ('superadmin', 'audit.read'),
+    ('superadmin', 'service_account.manage'),
+    ('superadmin', 'sla.manage');
+
 -- =====================
 -- SEED DATA - Categories
 -- =====================
 INSERT INTO
-    categories (name, department)
+    categories (
+        name,
+        department,
+        ack_target_hours,
+        resolution_target_hours
+    )
 VALUES (
         'Kebersihan Jalan',
-        'kebersihan'
+        'kebersihan',
+        24,
+        72
     ),
     (
         'Sampah Menumpuk',
-        'kebersihan'
+        'kebersihan',
+        24,
+        48
     ),
     (
         'Saluran Air Tersumbat',
-        'kebersihan'
+        'kebersihan',
+        24,
+        72
+    ),
+    (
+        'Wabah Penyakit',
+        'kesehatan',
+        4,
+        24
     ),
-    ('Wabah Penyakit', 'kesehatan'),
     (
         'Klinik Kurang Fasilitas',
-        'kesehatan'
+        'kesehatan',
+        48,
+        336
+    ),
+    (
+        'Vaksinasi',
+        'kesehatan',
+        24,
+        168
     ),
-    ('Vaksinasi', 'kesehatan'),
     (
         'Jalan Rusak',
-        'infrastruktur'
+        'infrastruktur',
+        48,
+        336
     ),
     (
         'Lampu Jalan Mati',
-        'infrastruktur'
+        'infrastruktur',
+        24,
+        120
     ),
     (
         'Jembatan Rusak',
-        'infrastruktur'
+        'infrastruktur',
+        24,
+        336
     );
 
 -- =====================
@@ -206,7 +807,8 @@ INSERT INTO
         password_hash,
         name,
         role,
-        department
+        department,
+        email_verified_at
     )
 VALUES (
         '11111111-1111-1111-1111-111111111111',
@@ -214,7 +816,8 @@ VALUES (
         '$2a$12$pWAZ3QeIFtafoCTTZ4hkQezmUYPy5NSndf3XDSMKAJWd7ol9uQtEq',
         'Budi Warga',
         'warga',
-        NULL
+        NULL,
+        NOW()
     ),
     (
         '22222222-2222-2222-2222-222222222222',
@@ -222,7 +825,8 @@ VALUES (
         '$2a$12$pWAZ3QeIFtafoCTTZ4hkQezmUYPy5NSndf3XDSMKAJWd7ol9uQtEq',
         'Admin Kebersihan',
         'admin_kebersihan',
-        'kebersihan'
+        'kebersihan',
+        NOW()
     ),
     (
         '33333333-3333-3333-3333-333333333333',
@@ -230,7 +834,8 @@ VALUES (
         '$2a$12$pWAZ3QeIFtafoCTTZ4hkQezmUYPy5NSndf3XDSMKAJWd7ol9uQtEq',
         'Admin Kesehatan',
         'admin_kesehatan',
-        'kesehatan'
+        'kesehatan',
+        NOW()
     ),
     (
         '44444444-4444-4444-4444-444444444444',
@@ -238,7 +843,26 @@ VALUES (
         '$2a$12$pWAZ3QeIFtafoCTTZ4hkQezmUYPy5NSndf3XDSMKAJWd7ol9uQtEq',
         'Admin Infrastruktur',
         'admin_infrastruktur',
-        'infrastruktur'
+        'infrastruktur',
+        NOW()
+    ),
+    (
+        '55555555-5555-5555-5555-555555555555',
+        'superadmin@test.com',
+        '$2a$12$pWAZ3QeIFtafoCTTZ4hkQezmUYPy5NSndf3XDSMKAJWd7ol9uQtEq',
+        'Super Admin',
+        'superadmin',
+        NULL,
+        NOW()
+    ),
+    (
+        '66666666-6666-6666-6666-666666666666',
+        'petugas_kebersihan@test.com',
+        '$2a$12$pWAZ3QeIFtafoCTTZ4hkQezmUYPy5NSndf3XDSMKAJWd7ol9uQtEq',
+        'Petugas Kebersihan',
+        'petugas_kebersihan',
+        'kebersihan',
+        NOW()
     );
 
 -- =====================
@@ -5,7 +5,13 @@ import { useRouter } from "next/navigation";
 import Navbar from "@/components/Navbar";
 import { useAuth } from "@/lib/auth";
 import { api } from "@/lib/api";
-import type { Report, ReportStatus } from "@/types";
+import type {
+  Officer,
+  Report,
+  ReportFilter,
+  ReportStatus,
+  SLAState,
+} from "@/types";
 
 const STATUS_OPTIONS: { value: ReportStatus; label: string }[] = [
   { value: "pending", label: "Pending" },
@@ -15,14 +21,56 @@ const STATUS_OPTIONS: { value: ReportStatus; label: string }[] = [
   { value: "rejected", label: "Ditolak" },
 ];
 
+// Mirrors the workflow report-service enforces
+const STATUS_TRANSITIONS: Record<ReportStatus, ReportStatus[]> = {
+  pending: ["accepted", "rejected"],
+  accepted: ["in_progress", "rejected"],
+  in_progress: ["completed"],
+  completed: ["in_progress"],
+  rejected: ["pending"],
+};
+
+const requiresReason = (from: ReportStatus, to: ReportStatus) =>
+  to === "rejected" || from === "completed" || from === "rejected";
+
+type ReportView = "all" | "mine" | "unassigned";
+
+const VIEW_FILTERS: Record<ReportView, ReportFilter> = {
+  all: {},
+  mine: { assignee: "me" },
+  unassigned: { unassigned: true },
+};
+
+const SLA_LABELS: Record<SLAState, string> = {
+  none: "Tanpa SLA",
+  on_track: "Sesuai Target",
+  at_risk: "Berisiko",
+  breached: "Terlambat",
+  met: "Tercapai",
+};
+
+// Assignment is closed once a report is done with, or merged elsewhere
+const canBeAssigned = (report: Report) =>
+  !report.duplicate_of &&
+  report.status !== "completed" &&
+  report.status !== "rejected";
+
 export default function AdminPage() {
-  const { user, isLoading: authLoading, isAdmin } = useAuth();
+  const { user, isLoading: authLoading, isAdmin, hasPermission } = useAuth();
   const router = useRouter();
 
   const [reports, setReports] = useState<Report[]>([]);
   const [isLoading, setIsLoading] = useState(true);
+  const [nextCursor, setNextCursor] = useState<string | undefined>();
+  const [isLoadingMore, setIsLoadingMore] = useState(false);
+  const [view, setView] = useState<ReportView>("all");
+  const [slaState, setSlaState] = useState<SLAState | "">("");
+  const [sortBySla, setSortBySla] = useState(false);
+  const [officers, setOfficers] = useState<Officer[]>([]);
   const [selectedReport, setSelectedReport] = useState<Report | null>(null);
   const [newStatus, setNewStatus] = useState<ReportStatus>("pending");
+  const [reason, setReason] = useState("");
+  const [photo, setPhoto] = useState<File | null>(null);
   const [isUpdating, setIsUpdating] = useState(false);
   const [message, setMessage] = useState({ type: "", text: "" });
 
@@ -36,17 +84,35 @@ export default function AdminPage() {
     }
   }, [authLoading, user, isAdmin, router]);
 
+  const canAssign = hasPermission("report.assign");
+
   useEffect(() => {
     if (user && isAdmin()) {
       loadReports();
     }
-  }, [user]);
+  }, [user, view, slaState, sortBySla]);
+
+  useEffect(() => {
+    if (user && canAssign) {
+      api
+        .getOfficers()
+        .then((response) => setOfficers(response.officers || []))
+        .catch((error) => console.error("Failed to load officers:", error));
+    }
+  }, [user, canAssign]);
+
+  const reportFilter = (): ReportFilter => ({
+    ...VIEW_FILTERS[view],
+    sla: slaState || undefined,
+    sort: sortBySla ? "sla" : undefined,
+  });
 
   const loadReports = async () => {
     setIsLoading(true);
     try {
-      const response = await api.getReports();
+      const response = await api.getReports(undefined, reportFilter());
       setReports(response.reports || []);
+      setNextCursor(response.next_cursor);
     } catch (error) {
       console.error("Failed to load reports:", error);
     } finally {
@@ -54,6 +120,20 @@ export default function AdminPage() {
     }
   };
 
+  const loadMoreReports = async () => {
+    if (!nextCursor) return;
+    setIsLoadingMore(true);
+    try {
+      const response = await api.getReports(nextCursor, reportFilter());
+      setReports((prev) => [...prev, ...(response.reports || [])]);
+      setNextCursor(response.next_cursor);
+    } catch (error) {
+      console.error("Failed to load more reports:", error);
+    } finally {
+      setIsLoadingMore(false);
+    }
+  };
+
   const handleStatusUpdate = async () => {
     if (!selectedReport) return;
 
@@ -61,7 +141,14 @@ export default function AdminPage() {
     setMessage({ type: "", text: "" });
 
     try {
-      await api.updateReportStatus(selectedReport.id, newStatus);
+      if (photo) {
+        await api.addReportAttachment(
+          selectedReport.id,
+          photo,
+          newStatus === "completed" ? "resolution" : "progress"
+        );
+      }
+      await api.updateReportStatus(selectedReport.id, newStatus, reason);
       setMessage({ type: "success", text: "Status berhasil diperbarui" });
       setSelectedReport(null);
       loadReports();
@@ -76,6 +163,27 @@ export default function AdminPage() {
     }
   };
 
+  const handleAssign = async (report: Report, officerId: string) => {
+    if (!officerId || officerId === report.assignee_id) return;
+
+    setMessage({ type: "", text: "" });
+    try {
+      if (report.assignee_id) {
+        await api.reassignReport(report.id, officerId);
+      } else {
+        await api.assignReport(report.id, officerId);
+      }
+      setMessage({ type: "success", text: "Petugas berhasil ditugaskan" });
+      loadReports();
+    } catch (error) {
+      setMessage({
+        type: "error",
+        text:
+          error instanceof Error ? error.message : "Gagal menugaskan petugas",
+      });
+    }
+  };
+
   const formatDate = (dateStr: string) => {
     return new Date(dateStr).toLocaleDateString("id-ID", {
       day: "numeric",
@@ -126,6 +234,44 @@ export default function AdminPage() {
         )}
 
         <div className="page-content">
+          <div style={{ display: "flex", gap: "1rem", flexWrap: "wrap" }}>
+            <div className="form-group" style={{ minWidth: "200px" }}>
+              <select
+                className="form-select"
+                value={view}
+                onChange={(e) => setView(e.target.value as ReportView)}
+              >
+                <option value="all">Semua laporan</option>
+                <option value="mine">Ditugaskan ke saya</option>
+                <option value="unassigned">Belum ditugaskan</option>
+              </select>
+            </div>
+            <div className="form-group" style={{ minWidth: "200px" }}>
+              <select
+                className="form-select"
+                value={slaState}
+                onChange={(e) => setSlaState(e.target.value as SLAState | "")}
+              >
+                <option value="">Semua SLA</option>
+                {(Object.keys(SLA_LABELS) as SLAState[]).map((state) => (
+                  <option key={state} value={state}>
+                    {SLA_LABELS[state]}
+                  </option>
+                ))}
+              </select>
+            </div>
+            <div className="form-group" style={{ minWidth: "200px" }}>
+              <select
+                className="form-select"
+                value={sortBySla ? "sla" : "newest"}
+                onChange={(e) => setSortBySla(e.target.value === "sla")}
+              >
+                <option value="newest">Terbaru</option>
+                <option value="sla">Tenggat terdekat</option>
+              </select>
+            </div>
+          </div>
+
           {isLoading ? (
             <div className="loading">
               <div className="spinner" />
@@ -158,6 +304,12 @@ export default function AdminPage() {
                     <th style={{ padding: "1rem", textAlign: "left" }}>
                       Status
                     </th>
+                    <th style={{ padding: "1rem", textAlign: "left" }}>
+                      SLA
+                    </th>
+                    <th style={{ padding: "1rem", textAlign: "left" }}>
+                      Petugas
+                    </th>
                     <th style={{ padding: "1rem", textAlign: "left" }}>
                       Tanggal
                     </th>
@@ -194,6 +346,52 @@ export default function AdminPage() {
                           {report.status.replace("_", " ")}
                         </span>
                       </td>
+                      <td style={{ padding: "1rem" }}>
+                        {report.sla_state && report.sla_state !== "none" ? (
+                          <>
+                            <span
+                              className={`status-badge sla-${report.sla_state}`}
+                            >
+                              {SLA_LABELS[report.sla_state]}
+                            </span>
+                            {report.sla_due_at && (
+                              <div
+                                style={{
+                                  fontSize: "0.75rem",
+                                  color: "var(--text-secondary)",
+                                  marginTop: "0.25rem",
+                                }}
+                              >
+                                {formatDate(report.sla_due_at)}
+                              </div>
+                            )}
+                          </>
+                        ) : (
+                          "-"
+                        )}
+                      </td>
+                      <td style={{ padding: "1rem", fontSize: "0.875rem" }}>
+                        {canAssign && canBeAssigned(report) ? (
+                          <select
+                            className="form-select"
+                            value={report.assignee_id ?? ""}
+                            onChange={(e) =>
+                              handleAssign(report, e.target.value)
+                            }
+                          >
+                            <option value="" disabled>
+                              Pilih petugas
+                            </option>
+                            {officers.map((officer) => (
+                              <option key={officer.id} value={officer.id}>
+                                {officer.name}
+                              </option>
+                            ))}
+                          </select>
+                        ) : (
+                          report.assignee_name || "-"
+                        )}
+                      </td>
                       <td
                         style={{
                           padding: "1rem",
@@ -208,7 +406,9 @@ export default function AdminPage() {
                           className="btn btn-secondary btn-sm"
                           onClick={() => {
                             setSelectedReport(report);
-                            setNewStatus(report.status);
+                            setNewStatus(STATUS_TRANSITIONS[report.status][0]);
+                            setReason("");
+                            setPhoto(null);
                           }}
                         >
                           Ubah Status
@@ -220,6 +420,18 @@ export default function AdminPage() {
               </table>
             </div>
           )}
+
+          {!isLoading && nextCursor && (
+            <div style={{ textAlign: "center", marginTop: "1.5rem" }}>
+              <button
+                className="btn btn-secondary"
+                onClick={loadMoreReports}
+                disabled={isLoadingMore}
+              >
+                {isLoadingMore ? "Memuat..." : "Muat Lebih Banyak"}
+              </button>
+            </div>
+          )}
         </div>
       </main>
 
@@ -244,7 +456,9 @@ export default function AdminPage() {
                 value={newStatus}
                 onChange={(e) => setNewStatus(e.target.value as ReportStatus)}
               >
-                {STATUS_OPTIONS.map((opt) => (
+                {STATUS_OPTIONS.filter((opt) =>
+                  STATUS_TRANSITIONS[selectedReport.status].includes(opt.value)
+                ).map((opt) => (
                   <option key={opt.value} value={opt.value}>
                     {opt.label}
                   </option>
@@ -252,6 +466,35 @@ export default function AdminPage() {
               </select>
             </div>
 
+            {requiresReason(selectedReport.status, newStatus) && (
+              <div className="form-group">
+                <label className="form-label">Alasan</label>
+                <textarea
+                  className="form-textarea"
+                  value={reason}
+                  onChange={(e) => setReason(e.target.value)}
+                  placeholder="Jelaskan alasan perubahan status kepada pelapor"
+                  required
+                />
+              </div>
+            )}
+
+            {(newStatus === "in_progress" || newStatus === "completed") && (
+              <div className="form-group">
+                <label className="form-label">
+                  {newStatus === "completed"
+                    ? "Foto Penyelesaian"
+                    : "Foto Progres (opsional)"}
+                </label>
+                <input
+                  type="file"
+                  className="form-input"
+                  accept="image/jpeg,image/png"
+                  onChange={(e) => setPhoto(e.target.files?.[0] ?? null)}
+                />
+              </div>
+            )}
+
             <div className="modal-actions">
               <button
                 className="btn btn-secondary"
@@ -262,7 +505,11 @@ export default function AdminPage() {
               <button
                 className="btn btn-primary"
                 onClick={handleStatusUpdate}
-                disabled={isUpdating}
+                disabled={
+                  isUpdating ||
+                  (requiresReason(selectedReport.status, newStatus) &&
+                    !reason.trim())
+                }
               >
                 {isUpdating ? "Menyimpan..." : "Simpan"}
               </button>
@@ -16,6 +16,12 @@ export default function DashboardPage() {
   const [reports, setReports] = useState<Report[]>([]);
   const [categories, setCategories] = useState<Category[]>([]);
   const [isLoading, setIsLoading] = useState(true);
+  const [nextCursor, setNextCursor] = useState<string | undefined>();
+  const [isLoadingMore, setIsLoadingMore] = useState(false);
+  const [filters, setFilters] = useState<{
+    search: string;
+    categoryId: number | null;
+  }>({ search: "", categoryId: null });
   const [showCreateModal, setShowCreateModal] = useState(false);
 
   const [title, setTitle] = useState("");
@@ -25,8 +31,10 @@ export default function DashboardPage() {
   const [newCategoryDept, setNewCategoryDept] = useState("");
   const [useNewCategory, setUseNewCategory] = useState(false);
   const [privacyLevel, setPrivacyLevel] = useState<PrivacyLevel>("public");
+  const [photos, setPhotos] = useState<File[]>([]);
   const [formError, setFormError] = useState("");
   const [formSuccess, setFormSuccess] = useState("");
+  const [possibleDuplicates, setPossibleDuplicates] = useState<Report[]>([]);
   const [isSubmitting, setIsSubmitting] = useState(false);
 
   useEffect(() => {
@@ -49,6 +57,8 @@ export default function DashboardPage() {
         api.getCategories(),
       ]);
       setReports(reportsRes.reports || []);
+      setNextCursor(reportsRes.next_cursor);
+      setFilters({ search: "", categoryId: null });
       setCategories(categoriesRes.categories || []);
       if (categoriesRes.categories?.length) {
         setCategoryId(categoriesRes.categories[0].id);
@@ -63,9 +73,11 @@ export default function DashboardPage() {
   const handleSearch = useCallback(
     async (search: string, catId: number | null) => {
       setIsLoading(true);
+      setFilters({ search, categoryId: catId });
       try {
         const response = await api.getMyReports(search, catId);
         setReports(response.reports || []);
+        setNextCursor(response.next_cursor);
       } catch (error) {
         console.error("Search failed:", error);
       } finally {
@@ -75,6 +87,24 @@ export default function DashboardPage() {
     []
   );
 
+  const handleLoadMore = async () => {
+    if (!nextCursor) return;
+    setIsLoadingMore(true);
+    try {
+      const response = await api.getMyReports(
+        filters.search,
+        filters.categoryId,
+        nextCursor
+      );
+      setReports((prev) => [...prev, ...(response.reports || [])]);
+      setNextCursor(response.next_cursor);
+    } catch (error) {
+      console.error("Failed to load more reports:", error);
+    } finally {
+      setIsLoadingMore(false);
+    }
+  };
+
   const handleCreateReport = async (e: React.FormEvent) => {
     e.preventDefault();
     setFormError("");
@@ -95,14 +125,30 @@ export default function DashboardPage() {
         payload.category_id = categoryId;
       }
 
-      await api.createReport(payload);
+      if (photos.length > 0) {
+        const uploads = await Promise.all(
+          photos.map((photo) => api.uploadAttachment(photo))
+        );
+        payload.attachment_ids = uploads.map((upload) => upload.attachment.id);
+      }
+
+      const response = await api.createReport(payload);
       setFormSuccess("Laporan berhasil dibuat!");
       setTitle("");
       setDescription("");
       setPrivacyLevel("public");
+      setPhotos([]);
       setNewCategoryName("");
       setNewCategoryDept("");
       setUseNewCategory(false);
+
+      // Leave the modal open so the citizen can look at the suggestions
+      if (response.possible_duplicates?.length) {
+        setPossibleDuplicates(response.possible_duplicates);
+        loadData();
+        return;
+      }
+
       setTimeout(() => {
         setShowCreateModal(false);
         setFormSuccess("");
@@ -117,6 +163,12 @@ export default function DashboardPage() {
     }
   };
 
+  const closeCreateModal = () => {
+    setShowCreateModal(false);
+    setFormSuccess("");
+    setPossibleDuplicates([]);
+  };
+
   if (authLoading || !user) {
     return (
       <>
@@ -181,13 +233,25 @@ export default function DashboardPage() {
               ))}
             </div>
           )}
+
+          {!isLoading && nextCursor && (
+            <div style={{ textAlign: "center", marginTop: "1.5rem" }}>
+              <button
+                className="btn btn-secondary"
+                onClick={handleLoadMore}
+                disabled={isLoadingMore}
+              >
+                {isLoadingMore ? "Memuat..." : "Muat Lebih Banyak"}
+              </button>
+            </div>
+          )}
         </div>
       </main>
 
       {showCreateModal && (
         <div
           className="modal-overlay"
-          onClick={() => setShowCreateModal(false)}
+          onClick={closeCreateModal}
         >
           <div className="modal" onClick={(e) => e.stopPropagation()}>
             <h2 className="modal-title">Buat Laporan Baru</h2>
@@ -199,6 +263,18 @@ export default function DashboardPage() {
               <div className="message message-success">{formSuccess}</div>
             )}
 
+            {possibleDuplicates.length > 0 && (
+              <div className="form-group">
+                <p className="form-label">
+                  Laporan serupa yang sudah ada. Anda bisa memberi dukungan
+                  (vote) pada laporan tersebut:
+                </p>
+                {possibleDuplicates.map((report) => (
+                  <ReportCard key={report.id} report={report} />
+                ))}
+              </div>
+            )}
+
             <form onSubmit={handleCreateReport}>
               <div className="form-group">
                 <label className="form-label">Judul Laporan</label>
@@ -308,11 +384,22 @@ export default function DashboardPage() {
                 </select>
               </div>
 
+              <div className="form-group">
+                <label className="form-label">Foto / Dokumen (opsional)</label>
+                <input
+                  type="file"
+                  className="form-input"
+                  accept="image/jpeg,image/png,application/pdf"
+                  multiple
+                  onChange={(e) => setPhotos(Array.from(e.target.files || []))}
+                />
+              </div>
+
               <div className="modal-actions">
                 <button
                   type="button"
                   className="btn btn-secondary"
-                  onClick={() => setShowCreateModal(false)}
+                  onClick={closeCreateModal}
                 >
                   Batal
                 </button>
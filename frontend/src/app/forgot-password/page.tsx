@@ -0,0 +1,81 @@
+"use client";
+
+import { useState } from "react";
+import Link from "next/link";
+import { api } from "@/lib/api";
+import Navbar from "@/components/Navbar";
+
+export default function ForgotPasswordPage() {
+  const [email, setEmail] = useState("");
+  const [error, setError] = useState("");
+  const [success, setSuccess] = useState("");
+  const [isLoading, setIsLoading] = useState(false);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError("");
+    setSuccess("");
+    setIsLoading(true);
+
+    try {
+      await api.forgotPassword(email);
+      setSuccess(
+        "Jika email terdaftar, tautan reset password telah dikirim ke email Anda."
+      );
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Permintaan gagal");
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  return (
+    <>
+      <Navbar />
+      <main className="container">
+        <div className="form-card card">
+          <h1 style={{ marginBottom: "0.5rem" }}>Lupa Password</h1>
+          <p style={{ color: "var(--text-secondary)", marginBottom: "2rem" }}>
+            Masukkan email akun CityConnect Anda
+          </p>
+
+          {error && <div className="message message-error">{error}</div>}
+          {success && <div className="message message-success">{success}</div>}
+
+          <form onSubmit={handleSubmit}>
+            <div className="form-group">
+              <label className="form-label">Email</label>
+              <input
+                type="email"
+                className="form-input"
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                placeholder="email@example.com"
+                required
+              />
+            </div>
+
+            <button
+              type="submit"
+              className="btn btn-primary"
+              style={{ width: "100%" }}
+              disabled={isLoading}
+            >
+              {isLoading ? "Loading..." : "Kirim Tautan Reset"}
+            </button>
+          </form>
+
+          <p
+            style={{
+              marginTop: "1.5rem",
+              textAlign: "center",
+              color: "var(--text-secondary)",
+            }}
+          >
+            <Link href="/login">Kembali ke Login</Link>
+          </p>
+        </div>
+      </main>
+    </>
+  );
+}
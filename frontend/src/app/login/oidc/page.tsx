@@ -0,0 +1,59 @@
+"use client";
+
+import { useEffect, useRef, useState } from "react";
+import { useRouter } from "next/navigation";
+import Link from "next/link";
+import { useAuth } from "@/lib/auth";
+import Navbar from "@/components/Navbar";
+
+const errorMessages: Record<string, string> = {
+  account_deactivated: "Akun Anda telah dinonaktifkan.",
+  email_in_use:
+    "Email ini sudah terdaftar sebagai akun CityConnect. Login dengan password, atau minta administrator menautkan akun SSO Anda.",
+  invalid_state: "Sesi login SSO kedaluwarsa. Silakan coba lagi.",
+};
+
+export default function OidcCallbackPage() {
+  const [error, setError] = useState("");
+  const { completeOidcLogin } = useAuth();
+  const router = useRouter();
+  // The login code is single-use; don't spend it twice on a re-render
+  const started = useRef(false);
+
+  useEffect(() => {
+    if (started.current) return;
+    started.current = true;
+
+    const params = new URLSearchParams(window.location.search);
+    const failure = params.get("error");
+    const code = params.get("code");
+    if (failure || !code) {
+      setError(errorMessages[failure ?? ""] ?? "Login SSO gagal");
+      return;
+    }
+
+    completeOidcLogin(code)
+      .then(() => router.replace("/dashboard"))
+      .catch((err) =>
+        setError(err instanceof Error ? err.message : "Login SSO gagal")
+      );
+  }, [completeOidcLogin, router]);
+
+  return (
+    <>
+      <Navbar />
+      <main className="container">
+        <div className="form-card card">
+          <h1 style={{ marginBottom: "2rem" }}>Login SSO</h1>
+
+          {!error && <p>Memproses login...</p>}
+          {error && <div className="message message-error">{error}</div>}
+
+          <p style={{ marginTop: "1.5rem", textAlign: "center" }}>
+            <Link href="/login">Ke halaman Login</Link>
+          </p>
+        </div>
+      </main>
+    </>
+  );
+}
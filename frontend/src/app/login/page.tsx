@@ -5,14 +5,22 @@ import { useRouter } from "next/navigation";
 import Link from "next/link";
 import { useAuth } from "@/lib/auth";
 import Navbar from "@/components/Navbar";
+import { api } from "@/lib/api";
+import type { MfaChallengeResponse, MfaEnrollResponse } from "@/types";
 
 export default function LoginPage() {
   const [email, setEmail] = useState("");
   const [password, setPassword] = useState("");
   const [error, setError] = useState("");
   const [isLoading, setIsLoading] = useState(false);
+  const [challenge, setChallenge] = useState<MfaChallengeResponse | null>(
+    null
+  );
+  const [enrollment, setEnrollment] = useState<MfaEnrollResponse | null>(null);
+  const [code, setCode] = useState("");
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
 
-  const { login } = useAuth();
+  const { login, completeMfaLogin } = useAuth();
   const router = useRouter();
 
   const handleSubmit = async (e: React.FormEvent) => {
@@ -21,7 +29,16 @@ export default function LoginPage() {
     setIsLoading(true);
 
     try {
-      await login(email, password);
+      const mfaChallenge = await login(email, password);
+      if (mfaChallenge) {
+        setChallenge(mfaChallenge);
+        if (mfaChallenge.mfa_enrollment_required) {
+          setEnrollment(
+            await api.loginMfaEnroll(mfaChallenge.challenge_token)
+          );
+        }
+        return;
+      }
       router.push("/dashboard");
     } catch (err) {
       setError(err instanceof Error ? err.message : "Login gagal");
@@ -30,6 +47,112 @@ export default function LoginPage() {
     }
   };
 
+  const handleMfaSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!challenge) return;
+    setError("");
+    setIsLoading(true);
+
+    try {
+      const codes = await completeMfaLogin(challenge.challenge_token, code);
+      if (codes && codes.length > 0) {
+        setRecoveryCodes(codes);
+        return;
+      }
+      router.push("/dashboard");
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Kode tidak valid");
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  if (recoveryCodes.length > 0) {
+    return (
+      <>
+        <Navbar />
+        <main className="container">
+          <div className="form-card card">
+            <h1 style={{ marginBottom: "0.5rem" }}>Kode Pemulihan</h1>
+            <p style={{ color: "var(--text-secondary)", marginBottom: "1rem" }}>
+              Simpan kode berikut di tempat aman. Setiap kode hanya bisa
+              dipakai sekali jika Anda kehilangan akses ke aplikasi
+              authenticator.
+            </p>
+            <pre style={{ marginBottom: "1.5rem" }}>
+              {recoveryCodes.join("\n")}
+            </pre>
+            <button
+              className="btn btn-primary"
+              style={{ width: "100%" }}
+              onClick={() => router.push("/dashboard")}
+            >
+              Lanjut
+            </button>
+          </div>
+        </main>
+      </>
+    );
+  }
+
+  if (challenge) {
+    return (
+      <>
+        <Navbar />
+        <main className="container">
+          <div className="form-card card">
+            <h1 style={{ marginBottom: "0.5rem" }}>Verifikasi Dua Langkah</h1>
+            <p style={{ color: "var(--text-secondary)", marginBottom: "2rem" }}>
+              {enrollment
+                ? "Akun admin wajib memakai autentikasi dua langkah. Tambahkan akun ini ke aplikasi authenticator, lalu masukkan kode 6 digit."
+                : "Masukkan kode 6 digit dari aplikasi authenticator atau salah satu kode pemulihan."}
+            </p>
+
+            {error && <div className="message message-error">{error}</div>}
+
+            {enrollment && (
+              <div className="form-group">
+                <label className="form-label">Secret</label>
+                <code style={{ wordBreak: "break-all" }}>
+                  {enrollment.secret}
+                </code>
+                <p style={{ marginTop: "0.5rem" }}>
+                  <a href={enrollment.provisioning_uri}>
+                    Buka di aplikasi authenticator
+                  </a>
+                </p>
+              </div>
+            )}
+
+            <form onSubmit={handleMfaSubmit}>
+              <div className="form-group">
+                <label className="form-label">Kode</label>
+                <input
+                  type="text"
+                  className="form-input"
+                  value={code}
+                  onChange={(e) => setCode(e.target.value)}
+                  placeholder="123456"
+                  autoComplete="one-time-code"
+                  required
+                />
+              </div>
+
+              <button
+                type="submit"
+                className="btn btn-primary"
+                style={{ width: "100%" }}
+                disabled={isLoading}
+              >
+                {isLoading ? "Loading..." : "Verifikasi"}
+              </button>
+            </form>
+          </div>
+        </main>
+      </>
+    );
+  }
+
   return (
     <>
       <Navbar />
@@ -77,6 +200,16 @@ export default function LoginPage() {
             </button>
           </form>
 
+          {process.env.NEXT_PUBLIC_OIDC_ENABLED === "true" && (
+            <a
+              href={api.oidcLoginUrl()}
+              className="btn btn-secondary"
+              style={{ display: "block", width: "100%", marginTop: "1rem", textAlign: "center" }}
+            >
+              Masuk dengan SSO Pemkot
+            </a>
+          )}
+
           <p
             style={{
               marginTop: "1.5rem",
@@ -85,6 +218,8 @@ export default function LoginPage() {
             }}
           >
             Belum punya akun? <Link href="/register">Daftar</Link>
+            <br />
+            <Link href="/forgot-password">Lupa password?</Link>
           </p>
         </div>
       </main>
@@ -10,7 +10,7 @@ export default function RegisterPage() {
   const [email, setEmail] = useState("");
   const [password, setPassword] = useState("");
   const [name, setName] = useState("");
-  const [role, setRole] = useState("warga");
+  const [inviteCode, setInviteCode] = useState("");
   const [error, setError] = useState("");
   const [success, setSuccess] = useState("");
   const [isLoading, setIsLoading] = useState(false);
@@ -25,7 +25,7 @@ export default function RegisterPage() {
     setIsLoading(true);
 
     try {
-      await register(email, password, name, role);
+      await register(email, password, name, inviteCode);
       setSuccess("Registrasi berhasil! Silakan login.");
       setTimeout(() => router.push("/login"), 2000);
     } catch (err) {
@@ -87,19 +87,14 @@ export default function RegisterPage() {
             </div>
 
             <div className="form-group">
-              <label className="form-label">Tipe Akun</label>
-              <select
-                className="form-select"
-                value={role}
-                onChange={(e) => setRole(e.target.value)}
-              >
-                <option value="warga">Warga</option>
-                <option value="admin_kebersihan">Admin Dinas Kebersihan</option>
-                <option value="admin_kesehatan">Admin Dinas Kesehatan</option>
-                <option value="admin_infrastruktur">
-                  Admin Dinas Infrastruktur
-                </option>
-              </select>
+              <label className="form-label">Kode Undangan (opsional)</label>
+              <input
+                type="text"
+                className="form-input"
+                value={inviteCode}
+                onChange={(e) => setInviteCode(e.target.value)}
+                placeholder="Hanya untuk admin dinas"
+              />
             </div>
 
             <button
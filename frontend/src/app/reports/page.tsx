@@ -11,6 +11,12 @@ export default function ReportsPage() {
   const [reports, setReports] = useState<Report[]>([]);
   const [categories, setCategories] = useState<Category[]>([]);
   const [isLoading, setIsLoading] = useState(true);
+  const [nextCursor, setNextCursor] = useState<string | undefined>();
+  const [isLoadingMore, setIsLoadingMore] = useState(false);
+  const [filters, setFilters] = useState<{
+    search: string;
+    categoryId: number | null;
+  }>({ search: "", categoryId: null });
 
   useEffect(() => {
     loadData();
@@ -24,6 +30,8 @@ export default function ReportsPage() {
         api.getCategories(),
       ]);
       setReports(reportsRes.reports || []);
+      setNextCursor(reportsRes.next_cursor);
+      setFilters({ search: "", categoryId: null });
       setCategories(categoriesRes.categories || []);
     } catch (error) {
       console.error("Failed to load data:", error);
@@ -35,9 +43,11 @@ export default function ReportsPage() {
   const handleSearch = useCallback(
     async (search: string, categoryId: number | null) => {
       setIsLoading(true);
+      setFilters({ search, categoryId });
       try {
         const response = await api.getPublicReports(search, categoryId);
         setReports(response.reports || []);
+        setNextCursor(response.next_cursor);
       } catch (error) {
         console.error("Search failed:", error);
       } finally {
@@ -47,6 +57,24 @@ export default function ReportsPage() {
     []
   );
 
+  const handleLoadMore = async () => {
+    if (!nextCursor) return;
+    setIsLoadingMore(true);
+    try {
+      const response = await api.getPublicReports(
+        filters.search,
+        filters.categoryId,
+        nextCursor
+      );
+      setReports((prev) => [...prev, ...(response.reports || [])]);
+      setNextCursor(response.next_cursor);
+    } catch (error) {
+      console.error("Failed to load more reports:", error);
+    } finally {
+      setIsLoadingMore(false);
+    }
+  };
+
   return (
     <>
       <Navbar />
@@ -83,6 +111,18 @@ export default function ReportsPage() {
               ))}
             </div>
           )}
+
+          {!isLoading && nextCursor && (
+            <div style={{ textAlign: "center", marginTop: "1.5rem" }}>
+              <button
+                className="btn btn-secondary"
+                onClick={handleLoadMore}
+                disabled={isLoadingMore}
+              >
+                {isLoadingMore ? "Memuat..." : "Muat Lebih Banyak"}
+              </button>
+            </div>
+          )}
         </div>
       </main>
     </>
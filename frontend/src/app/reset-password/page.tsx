@@ -0,0 +1,84 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { useRouter } from "next/navigation";
+import { api } from "@/lib/api";
+import Navbar from "@/components/Navbar";
+
+export default function ResetPasswordPage() {
+  const [token, setToken] = useState("");
+  const [password, setPassword] = useState("");
+  const [error, setError] = useState("");
+  const [success, setSuccess] = useState("");
+  const [isLoading, setIsLoading] = useState(false);
+
+  const router = useRouter();
+
+  useEffect(() => {
+    const params = new URLSearchParams(window.location.search);
+    setToken(params.get("token") || "");
+  }, []);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError("");
+    setSuccess("");
+    setIsLoading(true);
+
+    try {
+      await api.resetPassword(token, password);
+      setSuccess("Password berhasil diubah! Silakan login.");
+      setTimeout(() => router.push("/login"), 2000);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Reset password gagal");
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  return (
+    <>
+      <Navbar />
+      <main className="container">
+        <div className="form-card card">
+          <h1 style={{ marginBottom: "0.5rem" }}>Reset Password</h1>
+          <p style={{ color: "var(--text-secondary)", marginBottom: "2rem" }}>
+            Buat password baru untuk akun Anda
+          </p>
+
+          {!token && (
+            <div className="message message-error">
+              Tautan reset tidak valid
+            </div>
+          )}
+          {error && <div className="message message-error">{error}</div>}
+          {success && <div className="message message-success">{success}</div>}
+
+          <form onSubmit={handleSubmit}>
+            <div className="form-group">
+              <label className="form-label">Password Baru</label>
+              <input
+                type="password"
+                className="form-input"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                placeholder="Minimal 6 karakter"
+                minLength={6}
+                required
+              />
+            </div>
+
+            <button
+              type="submit"
+              className="btn btn-primary"
+              style={{ width: "100%" }}
+              disabled={isLoading || !token}
+            >
+              {isLoading ? "Loading..." : "Simpan Password"}
+            </button>
+          </form>
+        </div>
+      </main>
+    </>
+  );
+}
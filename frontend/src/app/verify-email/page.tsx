@@ -0,0 +1,45 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import Link from "next/link";
+import { api } from "@/lib/api";
+import Navbar from "@/components/Navbar";
+
+export default function VerifyEmailPage() {
+  const [error, setError] = useState("");
+  const [success, setSuccess] = useState("");
+
+  useEffect(() => {
+    const token = new URLSearchParams(window.location.search).get("token");
+    if (!token) {
+      setError("Tautan verifikasi tidak valid");
+      return;
+    }
+
+    api
+      .verifyEmail(token)
+      .then(() => setSuccess("Email Anda berhasil diverifikasi."))
+      .catch((err) =>
+        setError(err instanceof Error ? err.message : "Verifikasi gagal")
+      );
+  }, []);
+
+  return (
+    <>
+      <Navbar />
+      <main className="container">
+        <div className="form-card card">
+          <h1 style={{ marginBottom: "2rem" }}>Verifikasi Email</h1>
+
+          {!error && !success && <p>Memverifikasi...</p>}
+          {error && <div className="message message-error">{error}</div>}
+          {success && <div className="message message-success">{success}</div>}
+
+          <p style={{ marginTop: "1.5rem", textAlign: "center" }}>
+            <Link href="/login">Ke halaman Login</Link>
+          </p>
+        </div>
+      </main>
+    </>
+  );
+}
@@ -5,6 +5,22 @@ import { useState } from "react";
 import { api } from "@/lib/api";
 import { useAuth } from "@/lib/auth";
 
+// Renders search highlights as text nodes so report content is never
+// interpreted as HTML
+function Highlighted({ text }: { text: string }) {
+  return (
+    <>
+      {text.split(/(<mark>.*?<\/mark>)/g).map((part, i) =>
+        part.startsWith("<mark>") && part.endsWith("</mark>") ? (
+          <mark key={i}>{part.slice(6, -7)}</mark>
+        ) : (
+          part
+        )
+      )}
+    </>
+  );
+}
+
 interface ReportCardProps {
   report: Report;
   showVoting?: boolean;
@@ -80,13 +96,21 @@ export default function ReportCard({
         className="card-title"
         style={{ paddingRight: showVoting ? "60px" : "0" }}
       >
-        {report.title}
+        {report.highlight ? (
+          <Highlighted text={report.highlight.title} />
+        ) : (
+          report.title
+        )}
       </h3>
 
       <p className="card-description">
-        {report.description.length > 150
-          ? report.description.substring(0, 150) + "..."
-          : report.description}
+        {report.highlight ? (
+          <Highlighted text={report.highlight.description} />
+        ) : report.description.length > 150 ? (
+          report.description.substring(0, 150) + "..."
+        ) : (
+          report.description
+        )}
       </p>
 
       <div className="report-meta">
@@ -110,6 +134,12 @@ export default function ReportCard({
           <span className="report-meta-item">👤 {report.reporter_name}</span>
         )}
       </div>
+
+      {report.status_reason && (
+        <p className="report-meta-item" style={{ marginTop: "0.5rem" }}>
+          Alasan: {report.status_reason}
+        </p>
+      )}
     </div>
   );
 }
@@ -1,10 +1,22 @@
 import type {
   LoginRequest,
   LoginResponse,
+  MfaChallengeResponse,
+  MfaEnrollResponse,
   RegisterRequest,
+  UpdateProfileRequest,
   ReportListResponse,
   Report,
   CreateReportRequest,
+  CreateReportResponse,
+  DuplicatesResponse,
+  StatusHistoryEntry,
+  Assignment,
+  Officer,
+  ReportFilter,
+  Comment,
+  Attachment,
+  AttachmentRole,
   UpdateReportRequest,
   VoteRequest,
   VoteResponse,
@@ -28,15 +40,43 @@ class ApiClient {
     return localStorage.getItem("token");
   }
 
+  private getRefreshToken(): string | null {
+    if (typeof window === "undefined") return null;
+    return localStorage.getItem("refresh_token");
+  }
+
+  private async tryRefresh(): Promise<boolean> {
+    const refreshToken = this.getRefreshToken();
+    if (!refreshToken) return false;
+
+    const response = await fetch(`${this.baseUrl}/api/v1/auth/refresh`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ refresh_token: refreshToken }),
+    });
+    if (!response.ok) {
+      localStorage.removeItem("token");
+      localStorage.removeItem("refresh_token");
+      return false;
+    }
+
+    const data: LoginResponse = await response.json();
+    localStorage.setItem("token", data.token);
+    localStorage.setItem("refresh_token", data.refresh_token);
+    return true;
+  }
+
   private async request<T>(
     endpoint: string,
-    options: RequestInit = {}
+    options: RequestInit = {},
+    retried = false
   ): Promise<T> {
     const token = this.getToken();
-    const headers: HeadersInit = {
-      "Content-Type": "application/json",
-      ...options.headers,
-    };
+    // fetch sets the multipart boundary itself for FormData bodies
+    const headers: HeadersInit =
+      options.body instanceof FormData
+        ? { ...options.headers }
+        : { "Content-Type": "application/json", ...options.headers };
 
     if (token) {
       (headers as Record<string, string>)["Authorization"] = `Bearer ${token}`;
@@ -47,6 +87,15 @@ class ApiClient {
       headers,
     });
 
+    if (
+      response.status === 401 &&
+      !retried &&
+      !endpoint.startsWith("/api/v1/auth/") &&
+      (await this.tryRefresh())
+    ) {
+      return this.request<T>(endpoint, options, true);
+    }
+
     if (!response.ok) {
       const error = await response
         .json()
@@ -58,13 +107,41 @@ class ApiClient {
   }
 
   // Auth endpoints
-  async login(data: LoginRequest): Promise<LoginResponse> {
-    return this.request<LoginResponse>("/api/v1/auth/login", {
+  async login(
+    data: LoginRequest
+  ): Promise<LoginResponse | MfaChallengeResponse> {
+    return this.request("/api/v1/auth/login", {
       method: "POST",
       body: JSON.stringify(data),
     });
   }
 
+  async loginMfa(challengeToken: string, code: string): Promise<LoginResponse> {
+    return this.request<LoginResponse>("/api/v1/auth/login/mfa", {
+      method: "POST",
+      body: JSON.stringify({ challenge_token: challengeToken, code }),
+    });
+  }
+
+  async loginMfaEnroll(challengeToken: string): Promise<MfaEnrollResponse> {
+    return this.request<MfaEnrollResponse>("/api/v1/auth/login/mfa/enroll", {
+      method: "POST",
+      body: JSON.stringify({ challenge_token: challengeToken }),
+    });
+  }
+
+  // Full-page navigation target; the provider round trip ends on /login/oidc
+  oidcLoginUrl(): string {
+    return `${this.baseUrl}/api/v1/auth/oidc/login`;
+  }
+
+  async loginOidc(code: string): Promise<LoginResponse> {
+    return this.request<LoginResponse>("/api/v1/auth/oidc/token", {
+      method: "POST",
+      body: JSON.stringify({ code }),
+    });
+  }
+
   async register(
     data: RegisterRequest
   ): Promise<{ message: string; user: User }> {
@@ -74,18 +151,93 @@ class ApiClient {
     });
   }
 
+  async logout(refreshToken: string | null): Promise<{ message: string }> {
+    return this.request("/api/v1/auth/logout", {
+      method: "POST",
+      body: JSON.stringify({ refresh_token: refreshToken || "" }),
+    });
+  }
+
+  async forgotPassword(email: string): Promise<{ message: string }> {
+    return this.request("/api/v1/auth/password/forgot", {
+      method: "POST",
+      body: JSON.stringify({ email }),
+    });
+  }
+
+  async resetPassword(
+    token: string,
+    newPassword: string
+  ): Promise<{ message: string }> {
+    return this.request("/api/v1/auth/password/reset", {
+      method: "POST",
+      body: JSON.stringify({ token, new_password: newPassword }),
+    });
+  }
+
+  async verifyEmail(token: string): Promise<{ message: string }> {
+    return this.request("/api/v1/auth/email/verify", {
+      method: "POST",
+      body: JSON.stringify({ token }),
+    });
+  }
+
   async getMe(): Promise<User> {
     return this.request<User>("/api/v1/auth/me");
   }
 
+  async updateProfile(data: UpdateProfileRequest): Promise<LoginResponse> {
+    return this.request<LoginResponse>("/api/v1/auth/me", {
+      method: "PATCH",
+      body: JSON.stringify(data),
+    });
+  }
+
+  async changePassword(
+    currentPassword: string,
+    newPassword: string
+  ): Promise<LoginResponse> {
+    return this.request<LoginResponse>("/api/v1/auth/me/password", {
+      method: "POST",
+      body: JSON.stringify({
+        current_password: currentPassword,
+        new_password: newPassword,
+      }),
+    });
+  }
+
+  // Personal data download; returns the archive as a Blob to save
+  async exportData(format: "json" | "zip" = "json"): Promise<Blob> {
+    const response = await fetch(
+      `${this.baseUrl}/api/v1/auth/me/export?format=${format}`,
+      { headers: { Authorization: `Bearer ${this.getToken()}` } }
+    );
+    if (!response.ok) {
+      const error = await response
+        .json()
+        .catch(() => ({ error: "Request failed" }));
+      throw new Error(error.error || "Request failed");
+    }
+    return response.blob();
+  }
+
+  async deleteAccount(currentPassword: string): Promise<{ message: string }> {
+    return this.request("/api/v1/auth/me", {
+      method: "DELETE",
+      body: JSON.stringify({ current_password: currentPassword }),
+    });
+  }
+
   // Report endpoints
   async getPublicReports(
     search?: string,
-    categoryId?: number | null
+    categoryId?: number | null,
+    cursor?: string
   ): Promise<ReportListResponse> {
     const params = new URLSearchParams();
     if (search) params.append("search", search);
     if (categoryId) params.append("category_id", categoryId.toString());
+    if (cursor) params.append("cursor", cursor);
     const query = params.toString();
     return this.request<ReportListResponse>(
       `/api/v1/reports/public${query ? `?${query}` : ""}`
@@ -94,34 +246,102 @@ class ApiClient {
 
   async getMyReports(
     search?: string,
-    categoryId?: number | null
+    categoryId?: number | null,
+    cursor?: string
   ): Promise<ReportListResponse> {
     const params = new URLSearchParams();
     if (search) params.append("search", search);
     if (categoryId) params.append("category_id", categoryId.toString());
+    if (cursor) params.append("cursor", cursor);
     const query = params.toString();
     return this.request<ReportListResponse>(
       `/api/v1/reports/my${query ? `?${query}` : ""}`
     );
   }
 
-  async getReports(): Promise<ReportListResponse> {
-    return this.request<ReportListResponse>("/api/v1/reports/");
+  async getNearbyReports(
+    lat: number,
+    lng: number,
+    radius?: number,
+    cursor?: string
+  ): Promise<ReportListResponse> {
+    const params = new URLSearchParams({
+      lat: lat.toString(),
+      lng: lng.toString(),
+    });
+    if (radius) params.append("radius", radius.toString());
+    if (cursor) params.append("cursor", cursor);
+    return this.request<ReportListResponse>(
+      `/api/v1/reports/public/nearby?${params.toString()}`
+    );
+  }
+
+  async getReportsWithin(
+    bbox: [number, number, number, number],
+    cursor?: string
+  ): Promise<ReportListResponse> {
+    const params = new URLSearchParams({ bbox: bbox.join(",") });
+    if (cursor) params.append("cursor", cursor);
+    return this.request<ReportListResponse>(
+      `/api/v1/reports/public/within?${params.toString()}`
+    );
+  }
+
+  async getReports(
+    cursor?: string,
+    filter?: ReportFilter
+  ): Promise<ReportListResponse> {
+    const params = new URLSearchParams();
+    if (filter?.assignee) params.append("assignee", filter.assignee);
+    if (filter?.unassigned) params.append("unassigned", "true");
+    if (filter?.sla) params.append("sla", filter.sla);
+    if (filter?.sort) params.append("sort", filter.sort);
+    if (cursor) params.append("cursor", cursor);
+    const query = params.toString() ? `?${params.toString()}` : "";
+    return this.request<ReportListResponse>(`/api/v1/reports/${query}`);
+  }
+
+  async getAssignedReports(cursor?: string): Promise<ReportListResponse> {
+    const query = cursor ? `?cursor=${encodeURIComponent(cursor)}` : "";
+    return this.request<ReportListResponse>(`/api/v1/reports/assigned${query}`);
   }
 
   async getReport(id: string): Promise<Report> {
     return this.request<Report>(`/api/v1/reports/${id}`);
   }
 
-  async createReport(
-    data: CreateReportRequest
-  ): Promise<{ message: string; report: Report }> {
+  async createReport(data: CreateReportRequest): Promise<CreateReportResponse> {
     return this.request("/api/v1/reports/", {
       method: "POST",
       body: JSON.stringify(data),
     });
   }
 
+  async uploadAttachment(
+    file: File
+  ): Promise<{ message: string; attachment: Attachment }> {
+    const form = new FormData();
+    form.append("file", file);
+    return this.request("/api/v1/reports/attachments", {
+      method: "POST",
+      body: form,
+    });
+  }
+
+  async addReportAttachment(
+    id: string,
+    file: File,
+    role: AttachmentRole
+  ): Promise<{ message: string; attachment: Attachment }> {
+    const form = new FormData();
+    form.append("file", file);
+    form.append("role", role);
+    return this.request(`/api/v1/reports/${id}/attachments`, {
+      method: "POST",
+      body: form,
+    });
+  }
+
   async updateReport(
     id: string,
     data: UpdateReportRequest
@@ -134,11 +354,77 @@ class ApiClient {
 
   async updateReportStatus(
     id: string,
-    status: string
+    status: string,
+    reason?: string
   ): Promise<{ message: string }> {
     return this.request(`/api/v1/reports/${id}/status`, {
       method: "PATCH",
-      body: JSON.stringify({ status }),
+      body: JSON.stringify({ status, reason }),
+    });
+  }
+
+  async getStatusHistory(
+    id: string
+  ): Promise<{ history: StatusHistoryEntry[] }> {
+    return this.request(`/api/v1/reports/${id}/history`);
+  }
+
+  async getOfficers(): Promise<{ officers: Officer[] }> {
+    return this.request(`/api/v1/reports/officers`);
+  }
+
+  async assignReport(
+    id: string,
+    assigneeId: string,
+    note?: string
+  ): Promise<{ message: string; assignment: Assignment }> {
+    return this.request(`/api/v1/reports/${id}/assign`, {
+      method: "POST",
+      body: JSON.stringify({ assignee_id: assigneeId, note }),
+    });
+  }
+
+  async reassignReport(
+    id: string,
+    assigneeId: string,
+    note?: string
+  ): Promise<{ message: string; assignment: Assignment }> {
+    return this.request(`/api/v1/reports/${id}/reassign`, {
+      method: "POST",
+      body: JSON.stringify({ assignee_id: assigneeId, note }),
+    });
+  }
+
+  async getAssignments(id: string): Promise<{ assignments: Assignment[] }> {
+    return this.request(`/api/v1/reports/${id}/assignments`);
+  }
+
+  async getComments(id: string): Promise<{ comments: Comment[] }> {
+    return this.request(`/api/v1/reports/${id}/comments`);
+  }
+
+  async addComment(
+    id: string,
+    body: string,
+    parentId?: string
+  ): Promise<{ message: string; comment: Comment }> {
+    return this.request(`/api/v1/reports/${id}/comments`, {
+      method: "POST",
+      body: JSON.stringify({ body, parent_id: parentId }),
+    });
+  }
+
+  async getDuplicates(id: string): Promise<DuplicatesResponse> {
+    return this.request<DuplicatesResponse>(`/api/v1/reports/${id}/duplicates`);
+  }
+
+  async mergeReports(
+    id: string,
+    duplicateIds: string[]
+  ): Promise<{ message: string; report: Report }> {
+    return this.request(`/api/v1/reports/${id}/merge`, {
+      method: "POST",
+      body: JSON.stringify({ duplicate_ids: duplicateIds }),
     });
   }
 
@@ -146,6 +432,20 @@ class ApiClient {
     return this.request<CategoriesResponse>("/api/v1/reports/categories");
   }
 
+  async updateCategorySLA(
+    id: number,
+    ackTargetHours?: number,
+    resolutionTargetHours?: number
+  ): Promise<{ message: string; category: Category }> {
+    return this.request(`/api/v1/reports/categories/${id}/sla`, {
+      method: "PUT",
+      body: JSON.stringify({
+        ack_target_hours: ackTargetHours,
+        resolution_target_hours: resolutionTargetHours,
+      }),
+    });
+  }
+
   async createCategory(
     name: string,
     department: string
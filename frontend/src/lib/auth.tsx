@@ -7,21 +7,30 @@ import {
   useEffect,
   ReactNode,
 } from "react";
-import type { User } from "@/types";
+import type { LoginResponse, MfaChallengeResponse, User } from "@/types";
 import { api } from "./api";
 
 interface AuthContextType {
   user: User | null;
   token: string | null;
   isLoading: boolean;
-  login: (email: string, password: string) => Promise<void>;
+  login: (
+    email: string,
+    password: string
+  ) => Promise<MfaChallengeResponse | null>;
+  completeMfaLogin: (
+    challengeToken: string,
+    code: string
+  ) => Promise<string[] | undefined>;
+  completeOidcLogin: (code: string) => Promise<void>;
   register: (
     email: string,
     password: string,
     name: string,
-    role: string
+    inviteCode?: string
   ) => Promise<void>;
   logout: () => void;
+  hasPermission: (permission: string) => boolean;
   isAdmin: () => boolean;
 }
 
@@ -48,6 +57,7 @@ export function AuthProvider({ children }: { children: ReactNode }) {
         .then(setUser)
         .catch(() => {
           localStorage.removeItem("token");
+          localStorage.removeItem("refresh_token");
           setToken(null);
         })
         .finally(() => setIsLoading(false));
@@ -56,44 +66,76 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     }
   }, [isMounted]);
 
-  const login = async (email: string, password: string) => {
-    const response = await api.login({ email, password });
+  const startSession = (response: LoginResponse) => {
     localStorage.setItem("token", response.token);
+    localStorage.setItem("refresh_token", response.refresh_token);
     setToken(response.token);
     setUser(response.user);
   };
 
+  // Returns the challenge when the account needs a second factor
+  const login = async (email: string, password: string) => {
+    const response = await api.login({ email, password });
+    if ("mfa_required" in response) {
+      return response;
+    }
+    startSession(response);
+    return null;
+  };
+
+  const completeMfaLogin = async (challengeToken: string, code: string) => {
+    const response = await api.loginMfa(challengeToken, code);
+    startSession(response);
+    return response.recovery_codes;
+  };
+
+  const completeOidcLogin = async (code: string) => {
+    startSession(await api.loginOidc(code));
+  };
+
   const register = async (
     email: string,
     password: string,
     name: string,
-    role: string
+    inviteCode?: string
   ) => {
     await api.register({
       email,
       password,
       name,
-      role: role as
-        | "warga"
-        | "admin_kebersihan"
-        | "admin_kesehatan"
-        | "admin_infrastruktur",
+      invite_code: inviteCode || undefined,
     });
   };
 
   const logout = () => {
+    api.logout(localStorage.getItem("refresh_token")).catch(() => {});
     localStorage.removeItem("token");
+    localStorage.removeItem("refresh_token");
     setToken(null);
     setUser(null);
   };
 
-  const isAdmin = () => {
-    return user?.role?.startsWith("admin_") ?? false;
+  const hasPermission = (permission: string) => {
+    return user?.permissions?.includes(permission) ?? false;
   };
 
+  // The admin dashboard is the department report queue
+  const isAdmin = () => hasPermission("report.read.department");
+
   return (
     <AuthContext.Provider
-      value={{ user, token, isLoading, login, register, logout, isAdmin }}
+      value={{
+        user,
+        token,
+        isLoading,
+        login,
+        completeMfaLogin,
+        completeOidcLogin,
+        register,
+        logout,
+        hasPermission,
+        isAdmin,
+      }}
     >
       {children}
     </AuthContext.Provider>
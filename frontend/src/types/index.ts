@@ -1,8 +1,5 @@
-export type Role =
-  | "warga"
-  | "admin_kebersihan"
-  | "admin_kesehatan"
-  | "admin_infrastruktur";
+// Roles and their permissions are defined in the database
+export type Role = string;
 export type PrivacyLevel = "public" | "private" | "anonymous";
 export type ReportStatus =
   | "pending"
@@ -18,14 +15,28 @@ export interface User {
   name: string;
   role: Role;
   department?: string;
+  email_verified_at?: string;
+  pending_email?: string;
+  permissions: string[];
+}
+
+export interface UpdateProfileRequest {
+  name?: string;
+  email?: string;
+  current_password?: string;
 }
 
 export interface Category {
   id: number;
   name: string;
   department: string;
+  // SLA targets in hours from filing
+  ack_target_hours?: number;
+  resolution_target_hours?: number;
 }
 
+export type SLAState = "none" | "on_track" | "at_risk" | "breached" | "met";
+
 export interface Report {
   id: string;
   title: string;
@@ -35,13 +46,88 @@ export interface Report {
   location_lat?: number;
   location_lng?: number;
   photo_url?: string;
+  attachments?: Attachment[];
   privacy_level: PrivacyLevel;
   reporter_id?: string;
   reporter_name?: string;
   status: ReportStatus;
+  status_reason?: string;
   vote_score: number;
   created_at: string;
   updated_at: string;
+  rank?: number;
+  highlight?: ReportHighlight;
+  distance_m?: number;
+  duplicate_of?: string;
+  similarity?: number;
+  // Officer handling the report, only sent to department staff
+  assignee_id?: string;
+  assignee_name?: string;
+  assigned_at?: string;
+  // SLA deadlines, only sent to department staff
+  sla_state?: SLAState;
+  ack_due_at?: string;
+  resolution_due_at?: string;
+  sla_due_at?: string;
+}
+
+// Search matches wrapped in <mark></mark>; the rest is plain, unescaped text
+export interface ReportHighlight {
+  title: string;
+  description: string;
+}
+
+// actor_id and actor_name may be withheld from citizens
+export interface StatusHistoryEntry {
+  id: string;
+  report_id: string;
+  actor_id?: string;
+  actor_name?: string;
+  old_status: ReportStatus;
+  new_status: ReportStatus;
+  note?: string;
+  created_at: string;
+}
+
+export interface Assignment {
+  id: string;
+  report_id: string;
+  assignee_id?: string;
+  assignee_name?: string;
+  previous_assignee_id?: string;
+  previous_assignee_name?: string;
+  actor_id?: string;
+  actor_name?: string;
+  note?: string;
+  created_at: string;
+}
+
+export interface Officer {
+  id: string;
+  name: string;
+  role: string;
+}
+
+// Narrows the department listing; assignee may be "me". sort "sla" lists
+// reports with a running deadline, earliest first
+export interface ReportFilter {
+  assignee?: string;
+  unassigned?: boolean;
+  sla?: SLAState;
+  sort?: "newest" | "sla";
+}
+
+export interface Comment {
+  id: string;
+  report_id: string;
+  parent_id?: string;
+  author_id?: string;
+  author_name?: string;
+  is_reporter: boolean;
+  official_response: boolean;
+  body: string;
+  created_at: string;
+  replies?: Comment[];
 }
 
 export interface Notification {
@@ -63,7 +149,22 @@ export interface RegisterRequest {
   email: string;
   password: string;
   name: string;
-  role: Role;
+  invite_code?: string;
+}
+
+export type AttachmentRole = "evidence" | "progress" | "resolution";
+
+export interface Attachment {
+  id: string;
+  report_id?: string;
+  role: AttachmentRole;
+  content_type: string;
+  size_bytes: number;
+  width?: number;
+  height?: number;
+  url: string;
+  thumbnail_url?: string;
+  created_at: string;
 }
 
 export interface CreateReportRequest {
@@ -72,6 +173,7 @@ export interface CreateReportRequest {
   category_id: number;
   location_lat?: number;
   location_lng?: number;
+  attachment_ids?: string[];
   privacy_level: PrivacyLevel;
 }
 
@@ -86,12 +188,38 @@ export interface VoteRequest {
 
 export interface LoginResponse {
   token: string;
+  refresh_token: string;
+  expires_in: number;
   user: User;
+  recovery_codes?: string[];
+}
+
+export interface MfaChallengeResponse {
+  mfa_required: true;
+  mfa_enrollment_required?: boolean;
+  challenge_token: string;
+}
+
+export interface MfaEnrollResponse {
+  secret: string;
+  provisioning_uri: string;
 }
 
 export interface ReportListResponse {
   reports: Report[];
   total: number;
+  next_cursor?: string;
+}
+
+export interface CreateReportResponse {
+  message: string;
+  report: Report;
+  possible_duplicates?: Report[];
+}
+
+export interface DuplicatesResponse {
+  duplicates: Report[] | null;
+  candidates: Report[] | null;
 }
 
 export interface VoteResponse {
@@ -9,7 +9,9 @@ type Config struct {
 	Server   ServerConfig   `json:"server"`
 	Database DatabaseConfig `json:"database"`
 	RabbitMQ RabbitMQConfig `json:"rabbitmq"`
-	JWT      JWTConfig      `json:"jwt"`
+	JWKS     JWKSConfig     `json:"jwks"`
+	Auth     AuthConfig     `json:"auth"`
+	Internal InternalConfig `json:"internal"`
 }
 
 type ServerConfig struct {
@@ -31,8 +33,21 @@ type RabbitMQConfig struct {
 	Password string `json:"password"`
 }
 
-type JWTConfig struct {
-	Secret string `json:"secret"`
+type JWKSConfig struct {
+	URL          string `json:"url"`
+	CacheMinutes int    `json:"cache_minutes"`
+}
+
+// AuthConfig points at auth-service's /validate, which SSE tokens are
+// checked against before a stream is opened.
+type AuthConfig struct {
+	ValidateURL string `json:"validate_url"`
+}
+
+// InternalConfig guards the /internal endpoints other services call
+// directly. Token must match the X-Internal-Token header they send.
+type InternalConfig struct {
+	Token string `json:"token"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultCacheTTL = 10 * time.Minute
+	fetchTimeout    = 5 * time.Second
+	// Unknown kids trigger a refetch, but never more often than this so a
+	// flood of forged tokens can't hammer auth-service.
+	minRefetchInterval = 30 * time.Second
+)
+
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+}
+
+type cachedKey struct {
+	algorithm string
+	publicKey interface{}
+}
+
+// JWKSCache verifies tokens against the public keys published by
+// auth-service, so this service never needs the signing key itself.
+type JWKSCache struct {
+	url         string
+	ttl         time.Duration
+	client      *http.Client
+	mu          sync.RWMutex
+	keys        map[string]cachedKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func NewJWKSCache(url string, ttl time.Duration) *JWKSCache {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	return &JWKSCache{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: fetchTimeout},
+		keys:   make(map[string]cachedKey),
+	}
+}
+
+func (c *JWKSCache) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("missing kid")
+	}
+
+	key, ok, stale := c.lookup(kid)
+	if !ok || stale {
+		// On a failed refresh we keep verifying with the last known keys
+		if err := c.refresh(); err != nil {
+			log.Printf("jwks: refresh failed: %v", err)
+		}
+		key, ok, _ = c.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+	}
+
+	if token.Method.Alg() != key.algorithm {
+		return nil, errors.New("invalid signing method")
+	}
+	return key.publicKey, nil
+}
+
+func (c *JWKSCache) lookup(kid string) (cachedKey, bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	key, ok := c.keys[kid]
+	return key, ok, time.Since(c.fetchedAt) > c.ttl
+}
+
+func (c *JWKSCache) refresh() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.lastAttempt) < minRefetchInterval {
+		return nil
+	}
+	c.lastAttempt = time.Now()
+
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	keys := make(map[string]cachedKey)
+	for _, k := range body.Keys {
+		publicKey, err := parseJWK(k)
+		if err != nil {
+			log.Printf("jwks: skipping key %s: %v", k.KeyID, err)
+			continue
+		}
+		keys[k.KeyID] = cachedKey{algorithm: k.Algorithm, publicKey: publicKey}
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+func parseJWK(k jwk) (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
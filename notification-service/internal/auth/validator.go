@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const validateTimeout = 5 * time.Second
+
+// ErrTokenRejected means auth-service no longer accepts the token.
+var ErrTokenRejected = errors.New("token rejected")
+
+type validateResponse struct {
+	Valid  bool   `json:"valid"`
+	UserID string `json:"user_id"`
+}
+
+// Validator asks auth-service's /validate whether an access token is still
+// good. A valid signature says nothing about revoked tokens, signed-out
+// sessions or deactivated users; only auth-service knows about those.
+type Validator struct {
+	url    string
+	client *http.Client
+}
+
+func NewValidator(url string) *Validator {
+	return &Validator{
+		url:    url,
+		client: &http.Client{Timeout: validateTimeout},
+	}
+}
+
+// Validate returns the id of the user the token belongs to. clientIP is
+// passed on so the session's last-seen address stays the user's.
+func (v *Validator) Validate(token, clientIP string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, v.url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if clientIP != "" {
+		req.Header.Set("X-Real-IP", clientIP)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return "", ErrTokenRejected
+	default:
+		return "", fmt.Errorf("validate returned %s", resp.Status)
+	}
+
+	var body validateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if !body.Valid || body.UserID == "" {
+		return "", ErrTokenRejected
+	}
+	return body.UserID, nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"notification-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InternalHandler serves service-to-service calls. The gateway never
+// forwards /internal, and callers must also present the shared token.
+type InternalHandler struct {
+	notificationService *service.NotificationService
+	token               string
+}
+
+func NewInternalHandler(notificationService *service.NotificationService, token string) *InternalHandler {
+	return &InternalHandler{
+		notificationService: notificationService,
+		token:               token,
+	}
+}
+
+func (h *InternalHandler) ExportUserData(c *gin.Context) {
+	token := c.GetHeader("X-Internal-Token")
+	if h.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	export, err := h.notificationService.ExportUserNotifications(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
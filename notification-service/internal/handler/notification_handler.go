@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 
+	"notification-service/internal/auth"
 	"notification-service/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -15,13 +17,15 @@ import (
 
 type NotificationHandler struct {
 	notificationService *service.NotificationService
-	jwtSecret           string
+	jwks                *auth.JWKSCache
+	validator           *auth.Validator
 }
 
-func NewNotificationHandler(notificationService *service.NotificationService, jwtSecret string) *NotificationHandler {
+func NewNotificationHandler(notificationService *service.NotificationService, jwks *auth.JWKSCache, validator *auth.Validator) *NotificationHandler {
 	return &NotificationHandler{
 		notificationService: notificationService,
-		jwtSecret:           jwtSecret,
+		jwks:                jwks,
+		validator:           validator,
 	}
 }
 
@@ -51,12 +55,24 @@ func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
 			return
 		}
 
-		claims, err := h.validateToken(token)
-		if err != nil {
+		// The signature is checked locally so forged tokens never reach
+		// auth-service, which then rules on revocation, sign-out and
+		// deactivation
+		if _, err := h.validateToken(token); err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			return
 		}
-		userID = claims["user_id"].(string)
+		id, err := h.validator.Validate(token, c.ClientIP())
+		if err != nil {
+			if errors.Is(err, auth.ErrTokenRejected) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+				return
+			}
+			log.Printf("sse: validate token: %v", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "could not validate token"})
+			return
+		}
+		userID = id
 	}
 
 	uid, err := uuid.Parse(userID)
@@ -92,12 +108,8 @@ func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
 }
 
 func (h *NotificationHandler) validateToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return []byte(h.jwtSecret), nil
-	})
+	token, err := jwt.Parse(tokenString, h.jwks.Keyfunc,
+		jwt.WithValidMethods([]string{"RS256", "EdDSA"}))
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid token")
@@ -114,10 +114,13 @@ func NewNotificationConsumer(rmq *RabbitMQ, notificationRepo *repository.Notific
 }
 
 func (c *NotificationConsumer) Start() {
-	c.wg.Add(3)
+	c.wg.Add(6)
 	go c.consumeQueue(QueueStatusUpdates, c.handleStatusUpdate)
 	go c.consumeQueue(QueueReportCreated, c.handleReportCreated)
 	go c.consumeQueue(QueueVoteReceived, c.handleVoteReceived)
+	go c.consumeQueue(QueueCommentCreated, c.handleCommentCreated)
+	go c.consumeQueue(QueueReportAssigned, c.handleReportAssigned)
+	go c.consumeQueue(QueueSLABreached, c.handleSLABreached)
 	log.Println("consumers started")
 }
 
@@ -213,24 +216,42 @@ func (c *NotificationConsumer) handleStatusUpdate(msg amqp.Delivery) error {
 		return nil
 	}
 
-	err = c.notificationRepo.CreateStatusNotification(
-		reportID,
-		model.ReportStatus(statusUpdate.NewStatus),
-		statusUpdate.ReportTitle,
-	)
+	status := model.ReportStatus(statusUpdate.NewStatus)
+	if err := c.notifyStatusUpdate(reportID, statusUpdate.ReportTitle, statusUpdate.ReporterID, status, statusUpdate.Reason); err != nil {
+		return err
+	}
+
+	for _, duplicate := range statusUpdate.Duplicates {
+		duplicateID, err := uuid.Parse(duplicate.ReportID)
+		if err != nil {
+			log.Printf("status_update: bad duplicate report_id: %v", err)
+			continue
+		}
+		if err := c.notifyStatusUpdate(duplicateID, duplicate.ReportTitle, duplicate.ReporterID, status, statusUpdate.Reason); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifyStatusUpdate stores the status notification for a report's reporter
+// and pushes it to them if they are connected.
+func (c *NotificationConsumer) notifyStatusUpdate(reportID uuid.UUID, reportTitle, reporterIDStr string, status model.ReportStatus, reason string) error {
+	err := c.notificationRepo.CreateStatusNotification(reportID, status, reportTitle, reason)
 	if err != nil {
 		return err
 	}
 
-	if statusUpdate.ReporterID != "" {
-		reporterID, err := uuid.Parse(statusUpdate.ReporterID)
+	if reporterIDStr != "" {
+		reporterID, err := uuid.Parse(reporterIDStr)
 		if err == nil {
 			notification := &model.Notification{
 				ID:        uuid.New(),
 				UserID:    reporterID,
 				ReportID:  &reportID,
 				Title:     "Status Laporan Diperbarui",
-				Message:   "Laporan \"" + statusUpdate.ReportTitle + "\" telah diubah statusnya menjadi: " + statusUpdate.NewStatus,
+				Message:   model.StatusMessage(reportTitle, status, reason),
 				IsRead:    false,
 				CreatedAt: time.Now(),
 			}
@@ -294,6 +315,176 @@ func (c *NotificationConsumer) handleVoteReceived(msg amqp.Delivery) error {
 	return nil
 }
 
+func (c *NotificationConsumer) handleCommentCreated(msg amqp.Delivery) error {
+	var commentCreated model.CommentCreatedMessage
+	if err := json.Unmarshal(msg.Body, &commentCreated); err != nil {
+		log.Printf("comment: bad json: %v", err)
+		return nil
+	}
+
+	reportID, err := uuid.Parse(commentCreated.ReportID)
+	if err != nil {
+		log.Printf("comment: bad report_id: %v", err)
+		return nil
+	}
+
+	// the reporter doesn't need to hear about their own comments
+	if commentCreated.ReporterID == "" || commentCreated.IsReporter || commentCreated.ReporterID == commentCreated.AuthorID {
+		return nil
+	}
+
+	reporterID, err := uuid.Parse(commentCreated.ReporterID)
+	if err != nil {
+		log.Printf("comment: bad reporter_id: %v", err)
+		return nil
+	}
+
+	title := "Komentar Baru"
+	message := "Ada komentar baru pada laporan \"" + commentCreated.ReportTitle + "\""
+	if commentCreated.IsOfficial {
+		title = "Tanggapan Resmi"
+		message = "Petugas memberikan tanggapan resmi pada laporan \"" + commentCreated.ReportTitle + "\""
+	}
+
+	notification := &model.Notification{
+		ID:        uuid.New(),
+		UserID:    reporterID,
+		ReportID:  &reportID,
+		Title:     title,
+		Message:   message,
+		IsRead:    false,
+		CreatedAt: time.Now(),
+	}
+
+	if err := c.notificationRepo.Create(notification); err != nil {
+		return err
+	}
+
+	c.sseHub.SendToUser(notification)
+	return nil
+}
+
+func (c *NotificationConsumer) handleReportAssigned(msg amqp.Delivery) error {
+	var assigned model.ReportAssignedMessage
+	if err := json.Unmarshal(msg.Body, &assigned); err != nil {
+		log.Printf("assigned: bad json: %v", err)
+		return nil
+	}
+
+	reportID, err := uuid.Parse(assigned.ReportID)
+	if err != nil {
+		log.Printf("assigned: bad report_id: %v", err)
+		return nil
+	}
+
+	assigneeID, err := uuid.Parse(assigned.AssigneeID)
+	if err != nil {
+		log.Printf("assigned: bad assignee_id: %v", err)
+		return nil
+	}
+
+	message := "Anda ditugaskan menangani laporan \"" + assigned.ReportTitle + "\""
+	if assigned.Note != "" {
+		message += ". Catatan: " + assigned.Note
+	}
+
+	notifications := []*model.Notification{{
+		ID:        uuid.New(),
+		UserID:    assigneeID,
+		ReportID:  &reportID,
+		Title:     "Laporan Ditugaskan",
+		Message:   message,
+		IsRead:    false,
+		CreatedAt: time.Now(),
+	}}
+
+	// on a reassignment the previous officer learns the report left their list
+	if assigned.PreviousAssigneeID != "" {
+		previousID, err := uuid.Parse(assigned.PreviousAssigneeID)
+		if err != nil {
+			log.Printf("assigned: bad previous_assignee_id: %v", err)
+		} else {
+			notifications = append(notifications, &model.Notification{
+				ID:        uuid.New(),
+				UserID:    previousID,
+				ReportID:  &reportID,
+				Title:     "Laporan Dialihkan",
+				Message:   "Laporan \"" + assigned.ReportTitle + "\" telah dialihkan ke petugas lain",
+				IsRead:    false,
+				CreatedAt: time.Now(),
+			})
+		}
+	}
+
+	for _, notification := range notifications {
+		if err := c.notificationRepo.Create(notification); err != nil {
+			return err
+		}
+		c.sseHub.SendToUser(notification)
+	}
+
+	return nil
+}
+
+func (c *NotificationConsumer) handleSLABreached(msg amqp.Delivery) error {
+	var breached model.SLABreachedMessage
+	if err := json.Unmarshal(msg.Body, &breached); err != nil {
+		log.Printf("sla: bad json: %v", err)
+		return nil
+	}
+
+	reportID, err := uuid.Parse(breached.ReportID)
+	if err != nil {
+		log.Printf("sla: bad report_id: %v", err)
+		return nil
+	}
+
+	deadline := "penyelesaian"
+	if breached.Kind == "acknowledgement" {
+		deadline = "tanggapan awal"
+	}
+	due := time.Unix(breached.DueAt, 0).Format("02/01/2006 15:04")
+
+	// the assignee is told to act, the department's admins that it escalated;
+	// an admin who is also the assignee only gets the first
+	var notifications []*model.Notification
+	notified := make(map[uuid.UUID]bool)
+	add := func(id, title, message string) {
+		userID, err := uuid.Parse(id)
+		if err != nil || notified[userID] {
+			return
+		}
+		notified[userID] = true
+		notifications = append(notifications, &model.Notification{
+			ID:        uuid.New(),
+			UserID:    userID,
+			ReportID:  &reportID,
+			Title:     title,
+			Message:   message,
+			IsRead:    false,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	if breached.AssigneeID != "" {
+		add(breached.AssigneeID, "Batas Waktu Terlewati",
+			"Batas waktu "+deadline+" laporan \""+breached.ReportTitle+"\" terlewati ("+due+")")
+	}
+	for _, adminID := range breached.EscalateTo {
+		add(adminID, "Eskalasi SLA",
+			"Laporan \""+breached.ReportTitle+"\" melewati batas waktu "+deadline+" ("+due+")")
+	}
+
+	for _, notification := range notifications {
+		if err := c.notificationRepo.Create(notification); err != nil {
+			return err
+		}
+		c.sseHub.SendToUser(notification)
+	}
+
+	return nil
+}
+
 func (c *NotificationConsumer) Stop() {
 	close(c.done)
 	c.wg.Wait()
@@ -13,17 +13,26 @@ const (
 	ExchangeName    = "cityconnect.notifications"
 	DLXExchangeName = "cityconnect.notifications.dlx"
 
-	QueueStatusUpdates = "queue.status_updates"
-	QueueReportCreated = "queue.report_created"
-	QueueVoteReceived  = "queue.vote_received"
-
-	QueueStatusUpdatesDLQ = "queue.status_updates.dlq"
-	QueueReportCreatedDLQ = "queue.report_created.dlq"
-	QueueVoteReceivedDLQ  = "queue.vote_received.dlq"
-
-	RoutingKeyStatusUpdate  = "report.status.updated"
-	RoutingKeyReportCreated = "report.created"
-	RoutingKeyVoteReceived  = "report.vote.received"
+	QueueStatusUpdates  = "queue.status_updates"
+	QueueReportCreated  = "queue.report_created"
+	QueueVoteReceived   = "queue.vote_received"
+	QueueCommentCreated = "queue.comment_created"
+	QueueReportAssigned = "queue.report_assigned"
+	QueueSLABreached    = "queue.sla_breached"
+
+	QueueStatusUpdatesDLQ  = "queue.status_updates.dlq"
+	QueueReportCreatedDLQ  = "queue.report_created.dlq"
+	QueueVoteReceivedDLQ   = "queue.vote_received.dlq"
+	QueueCommentCreatedDLQ = "queue.comment_created.dlq"
+	QueueReportAssignedDLQ = "queue.report_assigned.dlq"
+	QueueSLABreachedDLQ    = "queue.sla_breached.dlq"
+
+	RoutingKeyStatusUpdate   = "report.status.updated"
+	RoutingKeyReportCreated  = "report.created"
+	RoutingKeyVoteReceived   = "report.vote.received"
+	RoutingKeyCommentCreated = "report.comment.created"
+	RoutingKeyReportAssigned = "report.assigned"
+	RoutingKeySLABreached    = "report.sla.breached"
 
 	reconnectDelay = 5 * time.Second
 	prefetchCount  = 10
@@ -55,6 +64,24 @@ var QueueConfigs = []QueueConfig{
 		DLQName:       QueueVoteReceivedDLQ,
 		DLQRoutingKey: "dlq.vote_received",
 	},
+	{
+		QueueName:     QueueCommentCreated,
+		RoutingKey:    RoutingKeyCommentCreated,
+		DLQName:       QueueCommentCreatedDLQ,
+		DLQRoutingKey: "dlq.comment_created",
+	},
+	{
+		QueueName:     QueueReportAssigned,
+		RoutingKey:    RoutingKeyReportAssigned,
+		DLQName:       QueueReportAssignedDLQ,
+		DLQRoutingKey: "dlq.report_assigned",
+	},
+	{
+		QueueName:     QueueSLABreached,
+		RoutingKey:    RoutingKeySLABreached,
+		DLQName:       QueueSLABreachedDLQ,
+		DLQRoutingKey: "dlq.sla_breached",
+	},
 }
 
 type RabbitMQ struct {
@@ -16,6 +16,16 @@ const (
 	StatusRejected   ReportStatus = "rejected"
 )
 
+// StatusMessage is the notification text a reporter gets when their report
+// changes status.
+func StatusMessage(reportTitle string, status ReportStatus, reason string) string {
+	message := "Laporan \"" + reportTitle + "\" telah diubah statusnya menjadi: " + string(status)
+	if reason != "" {
+		message += ". Alasan: " + reason
+	}
+	return message
+}
+
 type VoteType string
 
 const (
@@ -38,12 +48,26 @@ type NotificationListResponse struct {
 	UnreadCount   int            `json:"unread_count"`
 }
 
+type NotificationExport struct {
+	Notifications []Notification `json:"notifications"`
+}
+
 type StatusUpdateMessage struct {
 	ReportID    string `json:"report_id"`
 	ReportTitle string `json:"report_title"`
 	NewStatus   string `json:"new_status"`
+	Reason      string `json:"reason,omitempty"`
 	ReporterID  string `json:"reporter_id,omitempty"`
 	Timestamp   int64  `json:"timestamp"`
+
+	// Reports merged into this one, whose reporters are notified as well
+	Duplicates []DuplicateReport `json:"duplicates,omitempty"`
+}
+
+type DuplicateReport struct {
+	ReportID    string `json:"report_id"`
+	ReportTitle string `json:"report_title"`
+	ReporterID  string `json:"reporter_id,omitempty"`
 }
 
 type ReportCreatedMessage struct {
@@ -67,6 +91,38 @@ type VoteReceivedMessage struct {
 	Timestamp   int64  `json:"timestamp"`
 }
 
+type CommentCreatedMessage struct {
+	CommentID   string `json:"comment_id"`
+	ReportID    string `json:"report_id"`
+	ReportTitle string `json:"report_title"`
+	ReporterID  string `json:"reporter_id,omitempty"`
+	AuthorID    string `json:"author_id,omitempty"`
+	IsReporter  bool   `json:"is_reporter"`
+	IsOfficial  bool   `json:"official_response"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+type ReportAssignedMessage struct {
+	ReportID           string `json:"report_id"`
+	ReportTitle        string `json:"report_title"`
+	AssigneeID         string `json:"assignee_id"`
+	AssignedBy         string `json:"assigned_by,omitempty"`
+	Note               string `json:"note,omitempty"`
+	Timestamp          int64  `json:"timestamp"`
+	PreviousAssigneeID string `json:"previous_assignee_id,omitempty"`
+}
+
+type SLABreachedMessage struct {
+	ReportID    string   `json:"report_id"`
+	ReportTitle string   `json:"report_title"`
+	Department  string   `json:"department"`
+	Kind        string   `json:"kind"`
+	DueAt       int64    `json:"due_at"`
+	AssigneeID  string   `json:"assignee_id,omitempty"`
+	EscalateTo  []string `json:"escalate_to,omitempty"`
+	Timestamp   int64    `json:"timestamp"`
+}
+
 type ProcessedMessage struct {
 	MessageID   string    `json:"message_id"`
 	ProcessedAt time.Time `json:"processed_at"`
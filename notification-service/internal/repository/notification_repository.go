@@ -48,6 +48,27 @@ func (r *NotificationRepository) GetByUserID(userID uuid.UUID) ([]model.Notifica
 	}
 	defer rows.Close()
 
+	return scanNotifications(rows)
+}
+
+// GetAllByUserID is GetByUserID without the page limit, for data exports.
+func (r *NotificationRepository) GetAllByUserID(userID uuid.UUID) ([]model.Notification, error) {
+	query := `
+		SELECT id, user_id, report_id, title, message, is_read, created_at
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows)
+}
+
+func scanNotifications(rows *sql.Rows) ([]model.Notification, error) {
 	var notifications []model.Notification
 	for rows.Next() {
 		var n model.Notification
@@ -100,7 +121,7 @@ func (r *NotificationRepository) MarkAllAsRead(userID uuid.UUID) error {
 	return err
 }
 
-func (r *NotificationRepository) CreateStatusNotification(reportID uuid.UUID, newStatus model.ReportStatus, reportTitle string) error {
+func (r *NotificationRepository) CreateStatusNotification(reportID uuid.UUID, newStatus model.ReportStatus, reportTitle, reason string) error {
 	var reporterID sql.NullString
 	query := `SELECT reporter_id FROM reports WHERE id = $1`
 	err := r.db.QueryRow(query, reportID).Scan(&reporterID)
@@ -122,7 +143,7 @@ func (r *NotificationRepository) CreateStatusNotification(reportID uuid.UUID, ne
 		UserID:    userID,
 		ReportID:  &reportID,
 		Title:     "Status Laporan Diperbarui",
-		Message:   "Laporan \"" + reportTitle + "\" telah diubah statusnya menjadi: " + string(newStatus),
+		Message:   model.StatusMessage(reportTitle, newStatus, reason),
 		IsRead:    false,
 		CreatedAt: time.Now(),
 	}
@@ -46,6 +46,20 @@ func (s *NotificationService) GetUserNotifications(userIDStr string) (*model.Not
 	}, nil
 }
 
+// ExportUserNotifications returns every notification the user has received,
+// for auth-service's personal data export.
+func (s *NotificationService) ExportUserNotifications(userID uuid.UUID) (*model.NotificationExport, error) {
+	notifications, err := s.notificationRepo.GetAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if notifications == nil {
+		notifications = []model.Notification{}
+	}
+
+	return &model.NotificationExport{Notifications: notifications}, nil
+}
+
 func (s *NotificationService) MarkAsRead(notificationIDStr, userIDStr string) error {
 	notificationID, err := uuid.Parse(notificationIDStr)
 	if err != nil {
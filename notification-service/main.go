@@ -7,8 +7,10 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"notification-service/config"
+	"notification-service/internal/auth"
 	"notification-service/internal/handler"
 	"notification-service/internal/messaging"
 	"notification-service/internal/repository"
@@ -67,7 +69,12 @@ func main() {
 
 	notificationService := service.NewNotificationService(notificationRepo, sseHub)
 
-	notificationHandler := handler.NewNotificationHandler(notificationService, cfg.JWT.Secret)
+	jwks := auth.NewJWKSCache(cfg.JWKS.URL, time.Duration(cfg.JWKS.CacheMinutes)*time.Minute)
+
+	validator := auth.NewValidator(cfg.Auth.ValidateURL)
+
+	notificationHandler := handler.NewNotificationHandler(notificationService, jwks, validator)
+	internalHandler := handler.NewInternalHandler(notificationService, cfg.Internal.Token)
 
 	r := gin.Default()
 
@@ -82,6 +89,8 @@ func main() {
 		notifications.PATCH("/read-all", notificationHandler.MarkAllAsRead)
 	}
 
+	r.GET("/internal/users/:id/export", internalHandler.ExportUserData)
+
 	admin := r.Group("/admin")
 	{
 		admin.GET("/dlq/stats", notificationHandler.GetDLQStats)
@@ -6,10 +6,16 @@ import (
 )
 
 type Config struct {
-	Server    ServerConfig    `json:"server"`
-	Database  DatabaseConfig  `json:"database"`
-	RabbitMQ  RabbitMQConfig  `json:"rabbitmq"`
-	Anonymous AnonymousConfig `json:"anonymous"`
+	Server     ServerConfig    `json:"server"`
+	Database   DatabaseConfig  `json:"database"`
+	RabbitMQ   RabbitMQConfig  `json:"rabbitmq"`
+	Anonymous  AnonymousConfig `json:"anonymous"`
+	Internal   InternalConfig  `json:"internal"`
+	Duplicates DuplicateConfig `json:"duplicates"`
+	History    HistoryConfig   `json:"history"`
+	Storage    StorageConfig   `json:"storage"`
+	Workflow   WorkflowConfig  `json:"workflow"`
+	SLA        SLAConfig       `json:"sla"`
 }
 
 type ServerConfig struct {
@@ -35,6 +41,65 @@ type AnonymousConfig struct {
 	Salt string `json:"salt"`
 }
 
+// InternalConfig guards the /internal endpoints other services call
+// directly. Token must match the X-Internal-Token header they send.
+type InternalConfig struct {
+	Token string `json:"token"`
+}
+
+// DuplicateConfig tunes the duplicate suggestions shown when a report is
+// filed. MinSimilarity is a pg_trgm similarity between 0 and 1.
+type DuplicateConfig struct {
+	RadiusMeters   float64 `json:"radius_meters"`
+	MinSimilarity  float64 `json:"min_similarity"`
+	WindowDays     int     `json:"window_days"`
+	MaxSuggestions int     `json:"max_suggestions"`
+}
+
+// HistoryConfig controls what citizens see of a report's status history.
+// With HideActors set, entries do not say which admin made the change.
+type HistoryConfig struct {
+	HideActors bool `json:"hide_actors"`
+}
+
+// WorkflowConfig adds conditions to status changes. With
+// RequireResolutionPhoto set, a report can only be completed once its
+// department has attached a resolution photo.
+type WorkflowConfig struct {
+	RequireResolutionPhoto bool `json:"require_resolution_photo"`
+}
+
+// SLAConfig tunes the SLA job. A report is at risk once AtRiskRatio of the
+// time to its running deadline has passed; BatchSize caps the breaches
+// escalated per run.
+type SLAConfig struct {
+	CheckIntervalSeconds int     `json:"check_interval_seconds"`
+	AtRiskRatio          float64 `json:"at_risk_ratio"`
+	BatchSize            int     `json:"batch_size"`
+}
+
+// StorageConfig chooses where uploaded photos are kept. Driver "local" writes
+// them under LocalPath, "s3" to an S3-compatible bucket such as MinIO.
+type StorageConfig struct {
+	Driver         string   `json:"driver"`
+	LocalPath      string   `json:"local_path"`
+	S3             S3Config `json:"s3"`
+	MaxUploadBytes int64    `json:"max_upload_bytes"`
+	MaxPerReport   int      `json:"max_per_report"`
+	MaxDimension   int      `json:"max_dimension"`
+	ThumbnailSize  int      `json:"thumbnail_size"`
+}
+
+// S3Config addresses a bucket by path style, so Endpoint is the server URL
+// (for example http://minio:9000) and not a bucket hostname.
+type S3Config struct {
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	Bucket    string `json:"bucket"`
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+}
+
 func LoadConfig(path string) (*Config, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"report-service/internal/model"
+	"report-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AssignmentHandler struct {
+	assignmentService *service.AssignmentService
+}
+
+func NewAssignmentHandler(assignmentService *service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{assignmentService: assignmentService}
+}
+
+func (h *AssignmentHandler) GetOfficers(c *gin.Context) {
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if !perms.Has(model.PermReportAssign) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to assign reports"})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	officers, err := h.assignmentService.GetOfficers(perms, department)
+	if err != nil {
+		if errors.Is(err, service.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to assign reports"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"officers": officers})
+}
+
+func (h *AssignmentHandler) Assign(c *gin.Context) {
+	h.assign(c, false)
+}
+
+func (h *AssignmentHandler) Reassign(c *gin.Context) {
+	h.assign(c, true)
+}
+
+func (h *AssignmentHandler) assign(c *gin.Context, reassign bool) {
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if !perms.Has(model.PermReportAssign) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to assign reports"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var req model.AssignReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	var assignment *model.Assignment
+	if reassign {
+		assignment, err = h.assignmentService.Reassign(reportID, &req, perms, statusActor(c), department)
+	} else {
+		assignment, err = h.assignmentService.Assign(reportID, &req, perms, statusActor(c), department)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAssignment):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAlreadyAssigned), errors.Is(err, service.ErrNotAssigned),
+			errors.Is(err, service.ErrAssigneeChanged), errors.Is(err, service.ErrReportClosed),
+			errors.Is(err, service.ErrReportMerged):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAccessDenied), errors.Is(err, service.ErrReportNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "report not found or access denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Report assigned successfully",
+		"assignment": assignment,
+	})
+}
+
+func (h *AssignmentHandler) GetHistory(c *gin.Context) {
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if c.GetHeader("X-User-Role") == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	history, err := h.assignmentService.GetHistory(reportID, perms, department)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": history})
+}
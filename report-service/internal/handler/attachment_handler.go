@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"report-service/internal/imaging"
+	"report-service/internal/model"
+	"report-service/internal/service"
+	"report-service/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// room for the multipart headers around the file itself
+const multipartOverhead = 64 << 10
+
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	data, ok := h.readUpload(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := h.attachmentService.Upload(userID, data)
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "File uploaded successfully",
+		"attachment": attachment,
+	})
+}
+
+func (h *AttachmentHandler) AddToReport(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	data, ok := h.readUpload(c)
+	if !ok {
+		return
+	}
+
+	role := model.AttachmentRole(c.DefaultPostForm("role", string(model.AttachmentEvidence)))
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	attachment, err := h.attachmentService.AddToReport(reportID, role, data, perms, userID, department)
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "File uploaded successfully",
+		"attachment": attachment,
+	})
+}
+
+// readUpload reads the multipart "file" field, answering the request itself
+// when it is missing or too large.
+func (h *AttachmentHandler) readUpload(c *gin.Context) ([]byte, bool) {
+	maxBytes := h.attachmentService.MaxUploadBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return nil, false
+	}
+	if header.Size > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return nil, false
+	}
+
+	return data, true
+}
+
+func uploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUploadTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+	case errors.Is(err, imaging.ErrUnsupported):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "only JPEG and PNG images and PDF documents are accepted"})
+	case errors.Is(err, service.ErrInvalidAttachment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrReportMerged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAccessDenied), errors.Is(err, service.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found or access denied"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store file"})
+	}
+}
+
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	h.serve(c, false)
+}
+
+func (h *AttachmentHandler) GetThumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+func (h *AttachmentHandler) GetPublicAttachment(c *gin.Context) {
+	h.servePublic(c, false)
+}
+
+func (h *AttachmentHandler) GetPublicThumbnail(c *gin.Context) {
+	h.servePublic(c, true)
+}
+
+func (h *AttachmentHandler) serve(c *gin.Context, thumbnail bool) {
+	userID := c.GetHeader("X-User-ID")
+	userRole := c.GetHeader("X-User-Role")
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if userRole == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment id"})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	body, contentType, err := h.attachmentService.Open(id, thumbnail, perms, userID, department)
+	if err != nil {
+		openError(c, err, "attachment not found or access denied")
+		return
+	}
+	defer body.Close()
+
+	writeBlob(c, body, contentType, "private, max-age=3600")
+}
+
+func (h *AttachmentHandler) servePublic(c *gin.Context, thumbnail bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment id"})
+		return
+	}
+
+	body, contentType, err := h.attachmentService.OpenPublic(id, thumbnail)
+	if err != nil {
+		openError(c, err, "attachment not found")
+		return
+	}
+	defer body.Close()
+
+	writeBlob(c, body, contentType, "public, max-age=86400")
+}
+
+// openError answers a failed attachment lookup. Whatever keeps the caller
+// from the file is reported as notFound; anything else is a server error.
+func openError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, service.ErrAttachmentNotFound), errors.Is(err, service.ErrReportNotFound),
+		errors.Is(err, service.ErrAccessDenied), errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+	}
+}
+
+// writeBlob streams a stored file. Uploads are immutable, so they can be
+// cached; nosniff keeps browsers from second-guessing the content type, and
+// documents are offered as downloads instead of opening inline.
+func writeBlob(c *gin.Context, body io.Reader, contentType, cacheControl string) {
+	headers := map[string]string{
+		"Cache-Control":          cacheControl,
+		"X-Content-Type-Options": "nosniff",
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		headers["Content-Disposition"] = "attachment"
+	}
+	c.DataFromReader(http.StatusOK, -1, contentType, body, headers)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"report-service/internal/model"
+	"report-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	comments, err := h.commentService.GetComments(reportID, perms, userID, department)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+func (h *CommentHandler) AddComment(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var req model.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	comment, err := h.commentService.AddComment(reportID, &req, perms, userID, department)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidComment) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondReportError(c, err)
+		return
+	}
+
+	// The author's own name isn't joined in on insert
+	if comment.AuthorID != nil {
+		if name := c.GetHeader("X-User-Name"); name != "" {
+			comment.AuthorName = &name
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Comment added successfully",
+		"comment": comment,
+	})
+}
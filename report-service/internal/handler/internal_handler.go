@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"report-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InternalHandler serves service-to-service calls. The gateway never
+// forwards /internal, and callers must also present the shared token.
+type InternalHandler struct {
+	userDataService *service.UserDataService
+	token           string
+}
+
+func NewInternalHandler(userDataService *service.UserDataService, token string) *InternalHandler {
+	return &InternalHandler{
+		userDataService: userDataService,
+		token:           token,
+	}
+}
+
+func (h *InternalHandler) ExportUserData(c *gin.Context) {
+	userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.userDataService.Export(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+func (h *InternalHandler) AnonymiseUser(c *gin.Context) {
+	userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.userDataService.Anonymise(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *InternalHandler) authorize(c *gin.Context) (uuid.UUID, bool) {
+	token := c.GetHeader("X-Internal-Token")
+	if h.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -52,6 +53,10 @@ func (h *ReportHandler) CreateReport(c *gin.Context) {
 		}
 		category, err := h.reportService.GetOrCreateCategory(*req.NewCategoryName, *req.NewCategoryDepartment)
 		if err != nil {
+			if errors.Is(err, service.ErrInvalidDepartment) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create category: " + err.Error()})
 			return
 		}
@@ -63,16 +68,25 @@ func (h *ReportHandler) CreateReport(c *gin.Context) {
 		return
 	}
 
-	report, err := h.reportService.CreateReport(&req, userID, userName)
+	report, duplicates, err := h.reportService.CreateReport(&req, userID, userName)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidAttachment) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"message": "Report created successfully",
 		"report":  report,
-	})
+	}
+	if len(duplicates) > 0 {
+		response["possible_duplicates"] = duplicates
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
 func (h *ReportHandler) GetPublicReports(c *gin.Context) {
@@ -87,27 +101,73 @@ func (h *ReportHandler) GetPublicReports(c *gin.Context) {
 		}
 	}
 
+	limit, cursor := pageParams(c)
+
 	if search != "" || categoryID != nil {
-		response, err := h.reportService.SearchPublicReports(search, categoryID)
+		response, err := h.reportService.SearchPublicReports(search, categoryID, limit, cursor)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondListError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, response)
+		respondReportList(c, response)
 		return
 	}
 
-	response, err := h.reportService.GetPublicReports()
+	response, err := h.reportService.GetPublicReports(limit, cursor)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, response)
+	respondReportList(c, response)
+}
+
+func (h *ReportHandler) GetNearbyReports(c *gin.Context) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lat and lng are required"})
+		return
+	}
+
+	var radius float64
+	if radiusStr := c.Query("radius"); radiusStr != "" {
+		var err error
+		radius, err = strconv.ParseFloat(radiusStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid radius"})
+			return
+		}
+	}
+
+	limit, cursor := pageParams(c)
+	response, err := h.reportService.GetNearbyReports(lat, lng, radius, limit, cursor)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+	respondReportList(c, response)
+}
+
+func (h *ReportHandler) GetReportsWithin(c *gin.Context) {
+	box, ok := parseBBox(c.Query("bbox"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bbox must be min_lng,min_lat,max_lng,max_lat"})
+		return
+	}
+
+	limit, cursor := pageParams(c)
+	response, err := h.reportService.GetReportsWithin(box, limit, cursor)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+	respondReportList(c, response)
 }
 
 func (h *ReportHandler) GetReports(c *gin.Context) {
 	userRole := c.GetHeader("X-User-Role")
 	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
 
 	if userRole == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -119,9 +179,66 @@ func (h *ReportHandler) GetReports(c *gin.Context) {
 		department = &userDept
 	}
 
-	response, err := h.reportService.GetReports(userRole, department)
+	// assignee is an officer's ID, or "me" for the caller
+	var filter model.ReportFilter
+	switch assignee := c.Query("assignee"); assignee {
+	case "":
+	case "me":
+		id, err := uuid.Parse(c.GetHeader("X-User-ID"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		filter.AssigneeID = &id
+	default:
+		id, err := uuid.Parse(assignee)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid assignee"})
+			return
+		}
+		filter.AssigneeID = &id
+	}
+	filter.Unassigned = c.Query("unassigned") == "true"
+
+	if sla := c.Query("sla"); sla != "" {
+		state := model.SLAState(sla)
+		if !state.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sla must be none, on_track, at_risk, breached or met"})
+			return
+		}
+		filter.SLAState = &state
+	}
+	switch c.Query("sort") {
+	case "", "newest":
+	case "sla":
+		filter.SortBySLA = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be newest or sla"})
+		return
+	}
+
+	limit, cursor := pageParams(c)
+	response, err := h.reportService.GetReports(perms, department, filter, limit, cursor)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ReportHandler) GetAssignedReports(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, cursor := pageParams(c)
+	response, err := h.reportService.GetAssignedReports(userID, limit, cursor)
+	if err != nil {
+		respondListError(c, err)
 		return
 	}
 
@@ -147,19 +264,21 @@ func (h *ReportHandler) GetMyReports(c *gin.Context) {
 		}
 	}
 
+	limit, cursor := pageParams(c)
+
 	if search != "" || categoryID != nil {
-		response, err := h.reportService.SearchMyReports(userID, search, categoryID)
+		response, err := h.reportService.SearchMyReports(userID, search, categoryID, limit, cursor)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondListError(c, err)
 			return
 		}
 		c.JSON(http.StatusOK, response)
 		return
 	}
 
-	response, err := h.reportService.GetMyReports(userID)
+	response, err := h.reportService.GetMyReports(userID, limit, cursor)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(c, err)
 		return
 	}
 
@@ -170,6 +289,7 @@ func (h *ReportHandler) GetReportByID(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	userRole := c.GetHeader("X-User-Role")
 	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
 
 	if userRole == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -187,9 +307,9 @@ func (h *ReportHandler) GetReportByID(c *gin.Context) {
 		department = &userDept
 	}
 
-	report, err := h.reportService.GetReportByID(reportID, userRole, userID, department)
+	report, err := h.reportService.GetReportByID(reportID, perms, userID, department)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "report not found or access denied"})
+		respondReportError(c, err)
 		return
 	}
 
@@ -197,11 +317,11 @@ func (h *ReportHandler) GetReportByID(c *gin.Context) {
 }
 
 func (h *ReportHandler) UpdateStatus(c *gin.Context) {
-	userRole := c.GetHeader("X-User-Role")
 	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
 
-	if !strings.HasPrefix(userRole, "admin_") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "only admin can update report status"})
+	if !perms.Has(model.PermReportStatusUpdate) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to update report status"})
 		return
 	}
 
@@ -234,14 +354,124 @@ func (h *ReportHandler) UpdateStatus(c *gin.Context) {
 		department = &userDept
 	}
 
-	if err := h.reportService.UpdateReportStatus(reportID, req.Status, department); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "report not found or access denied"})
+	if err := h.reportService.UpdateReportStatus(reportID, req.Status, req.Reason, statusActor(c), department); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrReportMerged):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, service.ErrReasonRequired), errors.Is(err, service.ErrResolutionPhotoRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondReportError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Status updated successfully"})
 }
 
+func (h *ReportHandler) GetStatusHistory(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	userRole := c.GetHeader("X-User-Role")
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if userRole == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	history, err := h.reportService.GetStatusHistory(reportID, perms, userID, department)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+func (h *ReportHandler) GetDuplicates(c *gin.Context) {
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if !perms.Has(model.PermReportReadDepartment) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to review duplicates"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	response, err := h.reportService.GetDuplicates(reportID, department)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ReportHandler) MergeReports(c *gin.Context) {
+	userDept := c.GetHeader("X-User-Department")
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if !perms.Has(model.PermReportStatusUpdate) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to merge reports"})
+		return
+	}
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var req model.MergeReportsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var department *string
+	if userDept != "" {
+		department = &userDept
+	}
+
+	report, err := h.reportService.MergeReports(reportID, req.DuplicateIDs, statusActor(c), department)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMerge) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reports merged successfully",
+		"report":  report,
+	})
+}
+
 func (h *ReportHandler) Health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 }
@@ -291,25 +521,58 @@ func (h *ReportHandler) GetCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"categories": categories})
 }
 
-func (h *ReportHandler) CreateCategory(c *gin.Context) {
-	var req model.CreateCategoryRequest
+func (h *ReportHandler) UpdateCategorySLA(c *gin.Context) {
+	perms := model.ParsePermissions(c.GetHeader("X-User-Permissions"))
+
+	if !perms.Has(model.PermSLAManage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to change SLA targets"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	var req model.UpdateCategorySLARequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	validDepts := map[string]bool{
-		"kebersihan":    true,
-		"kesehatan":     true,
-		"infrastruktur": true,
+	category, err := h.reportService.UpdateCategorySLA(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSLA):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
 	}
-	if !validDepts[strings.ToLower(req.Department)] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department, must be kebersihan, kesehatan, or infrastruktur"})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "SLA targets updated successfully",
+		"category": category,
+	})
+}
+
+func (h *ReportHandler) CreateCategory(c *gin.Context) {
+	var req model.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	category, err := h.reportService.GetOrCreateCategory(req.Name, strings.ToLower(req.Department))
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidDepartment) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -319,3 +582,84 @@ func (h *ReportHandler) CreateCategory(c *gin.Context) {
 		"category": category,
 	})
 }
+
+// pageParams reads the limit and cursor of a listing request. A missing or
+// malformed limit falls back to the default page size.
+func pageParams(c *gin.Context) (int, string) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	return limit, c.Query("cursor")
+}
+
+// respondReportList writes a listing as JSON, or as a GeoJSON
+// FeatureCollection for ?format=geojson.
+func respondReportList(c *gin.Context, response *model.ReportListResponse) {
+	switch c.Query("format") {
+	case "", "json":
+		c.JSON(http.StatusOK, response)
+	case "geojson":
+		c.Header("Content-Type", "application/geo+json")
+		c.JSON(http.StatusOK, model.NewReportFeatureCollection(response))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or geojson"})
+	}
+}
+
+// parseBBox reads a bounding box in the GeoJSON order
+// min_lng,min_lat,max_lng,max_lat.
+func parseBBox(value string) (model.BoundingBox, bool) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return model.BoundingBox{}, false
+	}
+
+	var coords [4]float64
+	for i, part := range parts {
+		coord, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return model.BoundingBox{}, false
+		}
+		coords[i] = coord
+	}
+
+	return model.BoundingBox{
+		MinLng: coords[0],
+		MinLat: coords[1],
+		MaxLng: coords[2],
+		MaxLat: coords[3],
+	}, true
+}
+
+// statusActor identifies the admin or service account behind a status
+// change from the headers the gateway sets.
+func statusActor(c *gin.Context) model.StatusActor {
+	var actor model.StatusActor
+	if id, err := uuid.Parse(c.GetHeader("X-User-ID")); err == nil {
+		actor.ID = &id
+	}
+	if name := c.GetHeader("X-User-Name"); name != "" {
+		actor.Name = &name
+	}
+	return actor
+}
+
+// respondReportError answers a failed lookup of a single report. Missing
+// reports and reports the caller may not see get the same 404.
+func respondReportError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrAccessDenied) || errors.Is(err, service.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found or access denied"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidCursor) || errors.Is(err, service.ErrInvalidLocation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrAccessDenied) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,102 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+
+	// decoding is refused above this many pixels, whatever the file size
+	maxPixels = 40_000_000
+
+	photoQuality     = 85
+	thumbnailQuality = 80
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported image type")
+	ErrTooLarge    = errors.New("image dimensions too large")
+)
+
+type Result struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+	Thumbnail   []byte
+}
+
+// Process sniffs data, accepting only JPEG and PNG whatever the client
+// claimed, and re-encodes it. Only pixels survive re-encoding, so EXIF, GPS
+// and any other metadata are dropped; the EXIF orientation is applied first
+// so photos still display upright. Photos larger than maxDimension are
+// scaled down, and a JPEG thumbnail fitting thumbnailSize is generated.
+func Process(data []byte, maxDimension, thumbnailSize int) (*Result, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != ContentTypeJPEG && contentType != ContentTypePNG {
+		return nil, ErrUnsupported
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	var img image.Image
+	if contentType == ContentTypeJPEG {
+		img, err = jpeg.Decode(bytes.NewReader(data))
+		if err == nil {
+			img = orient(img, jpegOrientation(data))
+		}
+	} else {
+		img, err = png.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	img = fit(img, maxDimension)
+
+	var buf bytes.Buffer
+	if contentType == ContentTypeJPEG {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: photoQuality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// thumbnails are always JPEG, with transparency flattened onto white
+	thumb := fit(img, thumbnailSize)
+	flat := image.NewRGBA(thumb.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), thumb, thumb.Bounds().Min, draw.Over)
+
+	var thumbBuf bytes.Buffer
+	if err := jpeg.Encode(&thumbBuf, flat, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	return &Result{
+		Data:        buf.Bytes(),
+		ContentType: contentType,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Thumbnail:   thumbBuf.Bytes(),
+	}, nil
+}
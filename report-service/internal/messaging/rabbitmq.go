@@ -15,17 +15,26 @@ const (
 	ExchangeName    = "cityconnect.notifications"
 	DLXExchangeName = "cityconnect.notifications.dlx"
 
-	QueueStatusUpdates = "queue.status_updates"
-	QueueReportCreated = "queue.report_created"
-	QueueVoteReceived  = "queue.vote_received"
-
-	QueueStatusUpdatesDLQ = "queue.status_updates.dlq"
-	QueueReportCreatedDLQ = "queue.report_created.dlq"
-	QueueVoteReceivedDLQ  = "queue.vote_received.dlq"
-
-	RoutingKeyStatusUpdate  = "report.status.updated"
-	RoutingKeyReportCreated = "report.created"
-	RoutingKeyVoteReceived  = "report.vote.received"
+	QueueStatusUpdates  = "queue.status_updates"
+	QueueReportCreated  = "queue.report_created"
+	QueueVoteReceived   = "queue.vote_received"
+	QueueCommentCreated = "queue.comment_created"
+	QueueReportAssigned = "queue.report_assigned"
+	QueueSLABreached    = "queue.sla_breached"
+
+	QueueStatusUpdatesDLQ  = "queue.status_updates.dlq"
+	QueueReportCreatedDLQ  = "queue.report_created.dlq"
+	QueueVoteReceivedDLQ   = "queue.vote_received.dlq"
+	QueueCommentCreatedDLQ = "queue.comment_created.dlq"
+	QueueReportAssignedDLQ = "queue.report_assigned.dlq"
+	QueueSLABreachedDLQ    = "queue.sla_breached.dlq"
+
+	RoutingKeyStatusUpdate   = "report.status.updated"
+	RoutingKeyReportCreated  = "report.created"
+	RoutingKeyVoteReceived   = "report.vote.received"
+	RoutingKeyCommentCreated = "report.comment.created"
+	RoutingKeyReportAssigned = "report.assigned"
+	RoutingKeySLABreached    = "report.sla.breached"
 
 	reconnectDelay = 5 * time.Second
 	publishTimeout = 5 * time.Second
@@ -42,14 +51,27 @@ var QueueBindings = []QueueBinding{
 	{QueueStatusUpdates, RoutingKeyStatusUpdate, QueueStatusUpdatesDLQ, "dlq.status_updates"},
 	{QueueReportCreated, RoutingKeyReportCreated, QueueReportCreatedDLQ, "dlq.report_created"},
 	{QueueVoteReceived, RoutingKeyVoteReceived, QueueVoteReceivedDLQ, "dlq.vote_received"},
+	{QueueCommentCreated, RoutingKeyCommentCreated, QueueCommentCreatedDLQ, "dlq.comment_created"},
+	{QueueReportAssigned, RoutingKeyReportAssigned, QueueReportAssignedDLQ, "dlq.report_assigned"},
+	{QueueSLABreached, RoutingKeySLABreached, QueueSLABreachedDLQ, "dlq.sla_breached"},
 }
 
 type StatusUpdateMessage struct {
 	ReportID    string `json:"report_id"`
 	ReportTitle string `json:"report_title"`
 	NewStatus   string `json:"new_status"`
+	Reason      string `json:"reason,omitempty"`
 	ReporterID  string `json:"reporter_id,omitempty"`
 	Timestamp   int64  `json:"timestamp"`
+
+	// Reports merged into this one, whose reporters are notified as well
+	Duplicates []DuplicateReport `json:"duplicates,omitempty"`
+}
+
+type DuplicateReport struct {
+	ReportID    string `json:"report_id"`
+	ReportTitle string `json:"report_title"`
+	ReporterID  string `json:"reporter_id,omitempty"`
 }
 
 type ReportCreatedMessage struct {
@@ -73,6 +95,43 @@ type VoteReceivedMessage struct {
 	Timestamp   int64  `json:"timestamp"`
 }
 
+type CommentCreatedMessage struct {
+	CommentID   string `json:"comment_id"`
+	ReportID    string `json:"report_id"`
+	ReportTitle string `json:"report_title"`
+	ReporterID  string `json:"reporter_id,omitempty"`
+	AuthorID    string `json:"author_id,omitempty"`
+	IsReporter  bool   `json:"is_reporter"`
+	IsOfficial  bool   `json:"official_response"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+type ReportAssignedMessage struct {
+	ReportID    string `json:"report_id"`
+	ReportTitle string `json:"report_title"`
+	AssigneeID  string `json:"assignee_id"`
+	AssignedBy  string `json:"assigned_by,omitempty"`
+	Note        string `json:"note,omitempty"`
+	Timestamp   int64  `json:"timestamp"`
+
+	// Set on a reassignment, so the officer taking it off their list hears
+	// about it too
+	PreviousAssigneeID string `json:"previous_assignee_id,omitempty"`
+}
+
+// SLABreachedMessage reports a missed acknowledgement or resolution
+// deadline. EscalateTo holds the department's admins.
+type SLABreachedMessage struct {
+	ReportID    string   `json:"report_id"`
+	ReportTitle string   `json:"report_title"`
+	Department  string   `json:"department"`
+	Kind        string   `json:"kind"`
+	DueAt       int64    `json:"due_at"`
+	AssigneeID  string   `json:"assignee_id,omitempty"`
+	EscalateTo  []string `json:"escalate_to,omitempty"`
+	Timestamp   int64    `json:"timestamp"`
+}
+
 type RabbitMQ struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
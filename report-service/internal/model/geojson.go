@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeatureCollection is a GeoJSON (RFC 7946) rendering of a report listing.
+// Total and NextCursor are foreign members carrying the listing's paging.
+type FeatureCollection struct {
+	Type       string    `json:"type"`
+	Features   []Feature `json:"features"`
+	Total      int       `json:"total"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// Feature is one report. Reports filed without a location have a null
+// geometry.
+type Feature struct {
+	Type       string           `json:"type"`
+	ID         uuid.UUID        `json:"id"`
+	Geometry   *Point           `json:"geometry"`
+	Properties ReportProperties `json:"properties"`
+}
+
+// Point coordinates are longitude first, as GeoJSON requires.
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// ReportProperties flattens a report for GIS tools, which handle nested
+// objects poorly.
+type ReportProperties struct {
+	Title        string       `json:"title"`
+	Description  string       `json:"description"`
+	CategoryID   int          `json:"category_id"`
+	Category     string       `json:"category,omitempty"`
+	Department   string       `json:"department,omitempty"`
+	Status       ReportStatus `json:"status"`
+	PrivacyLevel PrivacyLevel `json:"privacy_level"`
+	VoteScore    int          `json:"vote_score"`
+	PhotoURL     *string      `json:"photo_url,omitempty"`
+	ReporterName *string      `json:"reporter_name,omitempty"`
+	Distance     *float64     `json:"distance_m,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+func NewReportFeatureCollection(list *ReportListResponse) *FeatureCollection {
+	collection := &FeatureCollection{
+		Type:       "FeatureCollection",
+		Features:   make([]Feature, 0, len(list.Reports)),
+		Total:      list.Total,
+		NextCursor: list.NextCursor,
+	}
+
+	for _, report := range list.Reports {
+		feature := Feature{
+			Type: "Feature",
+			ID:   report.ID,
+			Properties: ReportProperties{
+				Title:        report.Title,
+				Description:  report.Description,
+				CategoryID:   report.CategoryID,
+				Status:       report.Status,
+				PrivacyLevel: report.PrivacyLevel,
+				VoteScore:    report.VoteScore,
+				PhotoURL:     report.PhotoURL,
+				ReporterName: report.ReporterName,
+				Distance:     report.Distance,
+				CreatedAt:    report.CreatedAt,
+				UpdatedAt:    report.UpdatedAt,
+			},
+		}
+		if report.Category != nil {
+			feature.Properties.Category = report.Category.Name
+			feature.Properties.Department = report.Category.Department
+		}
+		if report.LocationLat != nil && report.LocationLng != nil {
+			feature.Geometry = &Point{
+				Type:        "Point",
+				Coordinates: [2]float64{*report.LocationLng, *report.LocationLat},
+			}
+		}
+
+		collection.Features = append(collection.Features, feature)
+	}
+
+	return collection
+}
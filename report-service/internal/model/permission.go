@@ -0,0 +1,33 @@
+package model
+
+import "strings"
+
+// Permission names granted through the roles tables in auth-service and
+// forwarded by the gateway in X-User-Permissions.
+const (
+	PermReportReadDepartment = "report.read.department"
+	PermReportStatusUpdate   = "report.status.update"
+	PermReportAssign         = "report.assign"
+	PermSLAManage            = "sla.manage"
+)
+
+type Permissions []string
+
+func ParsePermissions(header string) Permissions {
+	var perms Permissions
+	for _, p := range strings.Split(header, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+func (p Permissions) Has(name string) bool {
+	for _, perm := range p {
+		if perm == name {
+			return true
+		}
+	}
+	return false
+}
@@ -24,6 +24,80 @@ const (
 	StatusRejected   ReportStatus = "rejected"
 )
 
+// statusTransitions lists the statuses a report may move to from each
+// status. Completed and rejected reports can only be reopened.
+var statusTransitions = map[ReportStatus][]ReportStatus{
+	StatusPending:    {StatusAccepted, StatusRejected},
+	StatusAccepted:   {StatusInProgress, StatusRejected},
+	StatusInProgress: {StatusCompleted},
+	StatusCompleted:  {StatusInProgress},
+	StatusRejected:   {StatusPending},
+}
+
+func (s ReportStatus) CanTransitionTo(next ReportStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresReason reports whether moving to next has to be explained to the
+// reporter: rejections, and reopening a completed or rejected report.
+func (s ReportStatus) RequiresReason(next ReportStatus) bool {
+	return next == StatusRejected || s == StatusCompleted || s == StatusRejected
+}
+
+// AttachmentRole says who added an attachment and why: evidence comes from
+// the citizen, progress and resolution from the department handling it.
+type AttachmentRole string
+
+const (
+	AttachmentEvidence   AttachmentRole = "evidence"
+	AttachmentProgress   AttachmentRole = "progress"
+	AttachmentResolution AttachmentRole = "resolution"
+)
+
+func (r AttachmentRole) Valid() bool {
+	return r == AttachmentEvidence || r == AttachmentProgress || r == AttachmentResolution
+}
+
+// ByStaff reports whether only the report's department may add attachments
+// with this role.
+func (r AttachmentRole) ByStaff() bool {
+	return r == AttachmentProgress || r == AttachmentResolution
+}
+
+// SLAState tells how a report is doing against its category's SLA targets.
+// Once a deadline is missed the report stays breached, even after it is
+// closed.
+type SLAState string
+
+const (
+	SLANone     SLAState = "none"
+	SLAOnTrack  SLAState = "on_track"
+	SLAAtRisk   SLAState = "at_risk"
+	SLABreached SLAState = "breached"
+	SLAMet      SLAState = "met"
+)
+
+func (s SLAState) Valid() bool {
+	switch s {
+	case SLANone, SLAOnTrack, SLAAtRisk, SLABreached, SLAMet:
+		return true
+	}
+	return false
+}
+
+// SLAKind names the deadline a breach is about.
+type SLAKind string
+
+const (
+	SLAAcknowledgement SLAKind = "acknowledgement"
+	SLAResolution      SLAKind = "resolution"
+)
+
 type VoteType string
 
 const (
@@ -35,6 +109,10 @@ type Category struct {
 	ID         int    `json:"id"`
 	Name       string `json:"name"`
 	Department string `json:"department"`
+
+	// SLA targets in hours from filing; nil means no target
+	AckTargetHours        *int `json:"ack_target_hours,omitempty"`
+	ResolutionTargetHours *int `json:"resolution_target_hours,omitempty"`
 }
 
 type Report struct {
@@ -51,9 +129,147 @@ type Report struct {
 	ReporterName *string      `json:"reporter_name,omitempty"`
 	ReporterHash *string      `json:"-"`
 	Status       ReportStatus `json:"status"`
+	StatusReason *string      `json:"status_reason,omitempty"`
 	VoteScore    int          `json:"vote_score"`
 	CreatedAt    time.Time    `json:"created_at"`
 	UpdatedAt    time.Time    `json:"updated_at"`
+
+	// Set once an admin has merged this report into a canonical one
+	DuplicateOf *uuid.UUID `json:"duplicate_of,omitempty"`
+
+	// Officer handling the report, only shown to department staff
+	AssigneeID   *uuid.UUID `json:"assignee_id,omitempty"`
+	AssigneeName *string    `json:"assignee_name,omitempty"`
+	AssignedAt   *time.Time `json:"assigned_at,omitempty"`
+
+	// SLA deadlines kept by the SLA job, only shown to department staff.
+	// SLADueAt is whichever deadline is currently running.
+	SLAState        SLAState   `json:"sla_state,omitempty"`
+	AckDueAt        *time.Time `json:"ack_due_at,omitempty"`
+	ResolutionDueAt *time.Time `json:"resolution_due_at,omitempty"`
+	SLADueAt        *time.Time `json:"sla_due_at,omitempty"`
+
+	// Set only on full-text search results
+	Rank      *float32         `json:"rank,omitempty"`
+	Highlight *ReportHighlight `json:"highlight,omitempty"`
+
+	// Metres from the searched point, set only on nearby listings
+	Distance *float64 `json:"distance_m,omitempty"`
+
+	// Text similarity to a new report, set only on duplicate suggestions
+	Similarity *float32 `json:"similarity,omitempty"`
+
+	// Uploaded photos, set only when a single report is fetched
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// ReportHighlight holds the matched parts of a search result, with every
+// matching term wrapped in <mark></mark>. The surrounding text is not HTML
+// escaped.
+type ReportHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// StatusHistoryEntry records one status change of a report. ActorID and
+// ActorName may be withheld from citizens.
+type StatusHistoryEntry struct {
+	ID        uuid.UUID    `json:"id"`
+	ReportID  uuid.UUID    `json:"report_id"`
+	ActorID   *uuid.UUID   `json:"actor_id,omitempty"`
+	ActorName *string      `json:"actor_name,omitempty"`
+	OldStatus ReportStatus `json:"old_status"`
+	NewStatus ReportStatus `json:"new_status"`
+	Note      *string      `json:"note,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// StatusActor is whoever changes a report's status: an admin, or a service
+// account acting for an integration.
+type StatusActor struct {
+	ID   *uuid.UUID
+	Name *string
+}
+
+// Assignment records one assignment of a report to an officer. Previous
+// fields are empty on the first assignment.
+type Assignment struct {
+	ID                   uuid.UUID  `json:"id"`
+	ReportID             uuid.UUID  `json:"report_id"`
+	AssigneeID           *uuid.UUID `json:"assignee_id,omitempty"`
+	AssigneeName         *string    `json:"assignee_name,omitempty"`
+	PreviousAssigneeID   *uuid.UUID `json:"previous_assignee_id,omitempty"`
+	PreviousAssigneeName *string    `json:"previous_assignee_name,omitempty"`
+	ActorID              *uuid.UUID `json:"actor_id,omitempty"`
+	ActorName            *string    `json:"actor_name,omitempty"`
+	Note                 *string    `json:"note,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+// Officer is a department member reports can be assigned to.
+type Officer struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Role string    `json:"role"`
+}
+
+// ReportFilter narrows a department listing to the reports of one officer
+// or to the reports nobody has been assigned yet, and to one SLA state.
+// SortBySLA lists only reports with a running deadline, earliest first.
+type ReportFilter struct {
+	AssigneeID *uuid.UUID
+	Unassigned bool
+	SLAState   *SLAState
+	SortBySLA  bool
+}
+
+// SLABreach is a missed deadline the SLA job has yet to escalate.
+type SLABreach struct {
+	ReportID    uuid.UUID
+	ReportTitle string
+	Department  string
+	AssigneeID  *uuid.UUID
+	Kind        SLAKind
+	DueAt       time.Time
+}
+
+// Comment is a comment on a report. Replies are nested under the comment
+// they answer.
+type Comment struct {
+	ID         uuid.UUID  `json:"id"`
+	ReportID   uuid.UUID  `json:"report_id"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	AuthorID   *uuid.UUID `json:"author_id,omitempty"`
+	AuthorName *string    `json:"author_name,omitempty"`
+	IsReporter bool       `json:"is_reporter"`
+	IsOfficial bool       `json:"official_response"`
+	Body       string     `json:"body"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Replies    []Comment  `json:"replies,omitempty"`
+}
+
+// Attachment is an uploaded photo or document. The file is served by
+// report-service at URL; photos also have a thumbnail at ThumbnailURL.
+// Documents have no dimensions or thumbnail.
+type Attachment struct {
+	ID           uuid.UUID      `json:"id"`
+	ReportID     *uuid.UUID     `json:"report_id,omitempty"`
+	Role         AttachmentRole `json:"role,omitempty"`
+	UploaderID   *uuid.UUID     `json:"-"`
+	StorageKey   string         `json:"-"`
+	ThumbnailKey string         `json:"-"`
+	ContentType  string         `json:"content_type"`
+	SizeBytes    int64          `json:"size_bytes"`
+	Width        int            `json:"width,omitempty"`
+	Height       int            `json:"height,omitempty"`
+	URL          string         `json:"url"`
+	ThumbnailURL string         `json:"thumbnail_url,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type CreateCommentRequest struct {
+	Body     string     `json:"body" binding:"required"`
+	ParentID *uuid.UUID `json:"parent_id"`
 }
 
 type ReportVote struct {
@@ -82,7 +298,7 @@ type CreateReportRequest struct {
 	NewCategoryDepartment *string      `json:"new_category_department,omitempty"`
 	LocationLat           *float64     `json:"location_lat"`
 	LocationLng           *float64     `json:"location_lng"`
-	PhotoURL              *string      `json:"photo_url"`
+	AttachmentIDs         []uuid.UUID  `json:"attachment_ids"`
 	PrivacyLevel          PrivacyLevel `json:"privacy_level" binding:"required"`
 }
 
@@ -98,6 +314,23 @@ type UpdateReportRequest struct {
 
 type UpdateStatusRequest struct {
 	Status ReportStatus `json:"status" binding:"required"`
+	Reason string       `json:"reason"`
+}
+
+type AssignReportRequest struct {
+	AssigneeID uuid.UUID `json:"assignee_id" binding:"required"`
+	Note       string    `json:"note"`
+}
+
+// UpdateCategorySLARequest replaces both targets of a category; a missing
+// target removes it.
+type UpdateCategorySLARequest struct {
+	AckTargetHours        *int `json:"ack_target_hours"`
+	ResolutionTargetHours *int `json:"resolution_target_hours"`
+}
+
+type MergeReportsRequest struct {
+	DuplicateIDs []uuid.UUID `json:"duplicate_ids" binding:"required"`
 }
 
 type VoteRequest struct {
@@ -109,12 +342,74 @@ type VoteResponse struct {
 	UserVoteType *VoteType `json:"user_vote_type,omitempty"`
 }
 
+// ReportCursor is the position of the last report on a page. VoteScore,
+// Rank, Distance and SLADueAt are only compared by listings ordered by votes,
+// search relevance, distance or SLA deadline.
+type ReportCursor struct {
+	VoteScore int        `json:"v"`
+	Rank      float32    `json:"r,omitempty"`
+	Distance  float64    `json:"d,omitempty"`
+	SLADueAt  *time.Time `json:"s,omitempty"`
+	CreatedAt time.Time  `json:"t"`
+	ID        uuid.UUID  `json:"id"`
+}
+
+type ReportPage struct {
+	Limit int
+	After *ReportCursor
+}
+
+type BoundingBox struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// DuplicateQuery describes a report to find likely duplicates of. Lat, Lng
+// and Box are only set for reports with a location, VisibleTo only when the
+// suggestions are shown to a citizen.
+type DuplicateQuery struct {
+	ReportID      uuid.UUID
+	Title         string
+	Description   string
+	CategoryID    int
+	Lat           *float64
+	Lng           *float64
+	Box           BoundingBox
+	RadiusMeters  float64
+	MinSimilarity float64
+	Since         time.Time
+	VisibleTo     *uuid.UUID
+	Limit         int
+}
+
 type ReportListResponse struct {
-	Reports []Report `json:"reports"`
-	Total   int      `json:"total"`
+	Reports    []Report `json:"reports"`
+	Total      int      `json:"total"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// DuplicatesResponse lists the reports merged into a report and the open
+// reports that may still be duplicates of it.
+type DuplicatesResponse struct {
+	Duplicates []Report `json:"duplicates"`
+	Candidates []Report `json:"candidates"`
 }
 
 type NotificationListResponse struct {
 	Notifications []Notification `json:"notifications"`
 	UnreadCount   int            `json:"unread_count"`
 }
+
+// UserDataExport is report-service's part of a citizen's personal data
+// export.
+type UserDataExport struct {
+	Reports  []Report     `json:"reports"`
+	Votes    []ReportVote `json:"votes"`
+	Comments []Comment    `json:"comments"`
+}
+
+type AnonymiseResponse struct {
+	AnonymisedReports int64 `json:"anonymised_reports"`
+}
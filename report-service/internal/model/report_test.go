@@ -0,0 +1,58 @@
+package model
+
+import "testing"
+
+func TestReportStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to ReportStatus
+		want     bool
+	}{
+		{StatusPending, StatusAccepted, true},
+		{StatusPending, StatusRejected, true},
+		{StatusPending, StatusInProgress, false},
+		{StatusPending, StatusCompleted, false},
+		{StatusPending, StatusPending, false},
+		{StatusAccepted, StatusInProgress, true},
+		{StatusAccepted, StatusRejected, true},
+		{StatusAccepted, StatusPending, false},
+		{StatusAccepted, StatusCompleted, false},
+		{StatusInProgress, StatusCompleted, true},
+		{StatusInProgress, StatusRejected, false},
+		{StatusInProgress, StatusAccepted, false},
+		{StatusCompleted, StatusInProgress, true},
+		{StatusCompleted, StatusPending, false},
+		{StatusCompleted, StatusRejected, false},
+		{StatusRejected, StatusPending, true},
+		{StatusRejected, StatusAccepted, false},
+		{StatusRejected, StatusInProgress, false},
+		{"unknown", StatusPending, false},
+		{StatusPending, "unknown", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: CanTransitionTo = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestReportStatusRequiresReason(t *testing.T) {
+	tests := []struct {
+		from, to ReportStatus
+		want     bool
+	}{
+		{StatusPending, StatusAccepted, false},
+		{StatusPending, StatusRejected, true},
+		{StatusAccepted, StatusInProgress, false},
+		{StatusAccepted, StatusRejected, true},
+		{StatusInProgress, StatusCompleted, false},
+		{StatusCompleted, StatusInProgress, true},
+		{StatusRejected, StatusPending, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.RequiresReason(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: RequiresReason = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
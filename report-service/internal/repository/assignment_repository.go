@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"report-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var ErrOfficerNotFound = errors.New("officer not found")
+
+type AssignmentRepository struct {
+	db *sql.DB
+}
+
+func NewAssignmentRepository(db *sql.DB) *AssignmentRepository {
+	return &AssignmentRepository{db: db}
+}
+
+func (r *AssignmentRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+// FindOfficers lists the active users of a department whose role grants
+// permission, by name.
+func (r *AssignmentRepository) FindOfficers(department, permission string) ([]model.Officer, error) {
+	query := `
+		SELECT u.id, u.name, u.role
+		FROM users u
+		JOIN role_permissions rp ON rp.role = u.role AND rp.permission = $2
+		WHERE u.department = $1 AND u.is_active
+		ORDER BY u.name, u.id
+	`
+	rows, err := r.db.Query(query, department, permission)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	officers := []model.Officer{}
+	for rows.Next() {
+		var officer model.Officer
+		if err := rows.Scan(&officer.ID, &officer.Name, &officer.Role); err != nil {
+			return nil, err
+		}
+		officers = append(officers, officer)
+	}
+
+	return officers, rows.Err()
+}
+
+// FindOfficer looks up one user the way FindOfficers lists them.
+func (r *AssignmentRepository) FindOfficer(id uuid.UUID, department, permission string) (*model.Officer, error) {
+	query := `
+		SELECT u.id, u.name, u.role
+		FROM users u
+		JOIN role_permissions rp ON rp.role = u.role AND rp.permission = $3
+		WHERE u.id = $1 AND u.department = $2 AND u.is_active
+	`
+	var officer model.Officer
+	err := r.db.QueryRow(query, id, department, permission).Scan(&officer.ID, &officer.Name, &officer.Role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrOfficerNotFound
+		}
+		return nil, err
+	}
+	return &officer, nil
+}
+
+// AssignInTransaction hands a report to an officer and records it in the
+// report's assignment history. It returns false, changing nothing, when the
+// report's assignee is no longer previous, e.g. because another admin
+// assigned it first.
+func (r *AssignmentRepository) AssignInTransaction(tx *sql.Tx, assignment *model.Assignment) (bool, error) {
+	query := `
+		UPDATE reports SET assignee_id = $1, assigned_at = $2, updated_at = NOW()
+		WHERE id = $3 AND assignee_id IS NOT DISTINCT FROM $4
+	`
+	result, err := tx.Exec(query, assignment.AssigneeID, assignment.CreatedAt, assignment.ReportID, assignment.PreviousAssigneeID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	query = `
+		INSERT INTO report_assignments (id, report_id, assignee_id, previous_assignee_id, actor_id, actor_name, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = tx.Exec(query,
+		assignment.ID,
+		assignment.ReportID,
+		assignment.AssigneeID,
+		assignment.PreviousAssigneeID,
+		assignment.ActorID,
+		assignment.ActorName,
+		assignment.Note,
+		assignment.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// FindByReportID returns a report's assignments, oldest first.
+func (r *AssignmentRepository) FindByReportID(reportID uuid.UUID) ([]model.Assignment, error) {
+	query := `
+		SELECT ra.id, ra.report_id, ra.assignee_id, a.name, ra.previous_assignee_id, p.name,
+			ra.actor_id, ra.actor_name, ra.note, ra.created_at
+		FROM report_assignments ra
+		LEFT JOIN users a ON ra.assignee_id = a.id
+		LEFT JOIN users p ON ra.previous_assignee_id = p.id
+		WHERE ra.report_id = $1
+		ORDER BY ra.created_at, ra.id
+	`
+	rows, err := r.db.Query(query, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.Assignment{}
+	for rows.Next() {
+		var entry model.Assignment
+		var assigneeID, assigneeName, previousID, previousName sql.NullString
+		var actorID, actorName, note sql.NullString
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ReportID,
+			&assigneeID,
+			&assigneeName,
+			&previousID,
+			&previousName,
+			&actorID,
+			&actorName,
+			&note,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if assigneeID.Valid {
+			id, _ := uuid.Parse(assigneeID.String)
+			entry.AssigneeID = &id
+		}
+		if assigneeName.Valid {
+			entry.AssigneeName = &assigneeName.String
+		}
+		if previousID.Valid {
+			id, _ := uuid.Parse(previousID.String)
+			entry.PreviousAssigneeID = &id
+		}
+		if previousName.Valid {
+			entry.PreviousAssigneeName = &previousName.String
+		}
+		if actorID.Valid {
+			id, _ := uuid.Parse(actorID.String)
+			entry.ActorID = &id
+		}
+		if actorName.Valid {
+			entry.ActorName = &actorName.String
+		}
+		if note.Valid {
+			entry.Note = &note.String
+		}
+
+		history = append(history, entry)
+	}
+
+	return history, rows.Err()
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"report-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(attachment *model.Attachment) error {
+	query := `
+		INSERT INTO report_attachments (id, report_id, uploader_id, role, storage_key, thumbnail_key,
+			content_type, size_bytes, width, height, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	// Documents have no thumbnail or dimensions
+	var thumbnailKey sql.NullString
+	var width, height sql.NullInt64
+	if attachment.ThumbnailKey != "" {
+		thumbnailKey = sql.NullString{String: attachment.ThumbnailKey, Valid: true}
+		width = sql.NullInt64{Int64: int64(attachment.Width), Valid: true}
+		height = sql.NullInt64{Int64: int64(attachment.Height), Valid: true}
+	}
+
+	role := attachment.Role
+	if role == "" {
+		role = model.AttachmentEvidence
+	}
+
+	_, err := r.db.Exec(query,
+		attachment.ID,
+		attachment.ReportID,
+		attachment.UploaderID,
+		role,
+		attachment.StorageKey,
+		thumbnailKey,
+		attachment.ContentType,
+		attachment.SizeBytes,
+		width,
+		height,
+		attachment.CreatedAt,
+	)
+	return err
+}
+
+func (r *AttachmentRepository) FindByID(id uuid.UUID) (*model.Attachment, error) {
+	query := `
+		SELECT id, report_id, uploader_id, role, storage_key, thumbnail_key, content_type, size_bytes, width, height, created_at
+		FROM report_attachments
+		WHERE id = $1
+	`
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments, err := r.scanAttachments(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, ErrAttachmentNotFound
+	}
+
+	return &attachments[0], nil
+}
+
+func (r *AttachmentRepository) FindByReportID(reportID uuid.UUID) ([]model.Attachment, error) {
+	query := `
+		SELECT id, report_id, uploader_id, role, storage_key, thumbnail_key, content_type, size_bytes, width, height, created_at
+		FROM report_attachments
+		WHERE report_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := r.db.Query(query, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanAttachments(rows)
+}
+
+func (r *AttachmentRepository) CountByRole(reportID uuid.UUID, role model.AttachmentRole) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM report_attachments WHERE report_id = $1 AND role = $2
+	`, reportID, role).Scan(&count)
+	return count, err
+}
+
+// HasPhoto reports whether a report has at least one image with the role;
+// documents don't count.
+func (r *AttachmentRepository) HasPhoto(reportID uuid.UUID, role model.AttachmentRole) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM report_attachments
+			WHERE report_id = $1 AND role = $2 AND content_type LIKE 'image/%'
+		)
+	`, reportID, role).Scan(&exists)
+	return exists, err
+}
+
+// AttachInTransaction links the uploader's unattached uploads to a report as
+// evidence and returns how many were linked. On anonymous reports the uploader is
+// cleared so the photos cannot be traced back to the reporter.
+func (r *AttachmentRepository) AttachInTransaction(tx *sql.Tx, reportID uuid.UUID, ids []uuid.UUID, uploaderID uuid.UUID, anonymous bool) (int64, error) {
+	query := `
+		UPDATE report_attachments
+		SET report_id = $1,
+			role = 'evidence',
+			uploader_id = CASE WHEN $4::boolean THEN NULL ELSE uploader_id END
+		WHERE id = ANY($2::uuid[]) AND uploader_id = $3 AND report_id IS NULL
+	`
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id.String()
+	}
+	result, err := tx.Exec(query, reportID, pq.Array(keys), uploaderID, anonymous)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *AttachmentRepository) scanAttachments(rows *sql.Rows) ([]model.Attachment, error) {
+	attachments := []model.Attachment{}
+	for rows.Next() {
+		var attachment model.Attachment
+		var reportID, uploaderID, thumbnailKey sql.NullString
+		var width, height sql.NullInt64
+
+		err := rows.Scan(
+			&attachment.ID,
+			&reportID,
+			&uploaderID,
+			&attachment.Role,
+			&attachment.StorageKey,
+			&thumbnailKey,
+			&attachment.ContentType,
+			&attachment.SizeBytes,
+			&width,
+			&height,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if reportID.Valid {
+			id, _ := uuid.Parse(reportID.String)
+			attachment.ReportID = &id
+		}
+		if uploaderID.Valid {
+			id, _ := uuid.Parse(uploaderID.String)
+			attachment.UploaderID = &id
+		}
+		if thumbnailKey.Valid {
+			attachment.ThumbnailKey = thumbnailKey.String
+		}
+		attachment.Width = int(width.Int64)
+		attachment.Height = int(height.Int64)
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"report-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var ErrCommentNotFound = errors.New("comment not found")
+
+type CommentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) CreateInTransaction(tx *sql.Tx, comment *model.Comment) error {
+	query := `
+		INSERT INTO report_comments (id, report_id, parent_id, author_id, is_reporter, is_official, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := tx.Exec(query,
+		comment.ID,
+		comment.ReportID,
+		comment.ParentID,
+		comment.AuthorID,
+		comment.IsReporter,
+		comment.IsOfficial,
+		comment.Body,
+		comment.CreatedAt,
+	)
+	return err
+}
+
+func (r *CommentRepository) FindByID(id uuid.UUID) (*model.Comment, error) {
+	query := `
+		SELECT rc.id, rc.report_id, rc.parent_id, rc.author_id, u.name, rc.is_reporter, rc.is_official, rc.body, rc.created_at
+		FROM report_comments rc
+		LEFT JOIN users u ON rc.author_id = u.id
+		WHERE rc.id = $1
+	`
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments, err := r.scanComments(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, ErrCommentNotFound
+	}
+	return &comments[0], nil
+}
+
+// FindByReportID returns every comment on a report, oldest first, with
+// replies not yet nested.
+func (r *CommentRepository) FindByReportID(reportID uuid.UUID) ([]model.Comment, error) {
+	query := `
+		SELECT rc.id, rc.report_id, rc.parent_id, rc.author_id, u.name, rc.is_reporter, rc.is_official, rc.body, rc.created_at
+		FROM report_comments rc
+		LEFT JOIN users u ON rc.author_id = u.id
+		WHERE rc.report_id = $1
+		ORDER BY rc.created_at, rc.id
+	`
+	rows, err := r.db.Query(query, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanComments(rows)
+}
+
+func (r *CommentRepository) FindByAuthorID(authorID uuid.UUID) ([]model.Comment, error) {
+	query := `
+		SELECT rc.id, rc.report_id, rc.parent_id, rc.author_id, u.name, rc.is_reporter, rc.is_official, rc.body, rc.created_at
+		FROM report_comments rc
+		LEFT JOIN users u ON rc.author_id = u.id
+		WHERE rc.author_id = $1
+		ORDER BY rc.created_at DESC
+	`
+	rows, err := r.db.Query(query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanComments(rows)
+}
+
+func (r *CommentRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+func (r *CommentRepository) scanComments(rows *sql.Rows) ([]model.Comment, error) {
+	comments := []model.Comment{}
+	for rows.Next() {
+		var comment model.Comment
+		var parentID, authorID, authorName sql.NullString
+
+		err := rows.Scan(
+			&comment.ID,
+			&comment.ReportID,
+			&parentID,
+			&authorID,
+			&authorName,
+			&comment.IsReporter,
+			&comment.IsOfficial,
+			&comment.Body,
+			&comment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if parentID.Valid {
+			id, _ := uuid.Parse(parentID.String)
+			comment.ParentID = &id
+		}
+		if authorID.Valid {
+			id, _ := uuid.Parse(authorID.String)
+			comment.AuthorID = &id
+		}
+		if authorName.Valid {
+			comment.AuthorName = &authorName.String
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
@@ -2,12 +2,19 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
 	"report-service/internal/model"
 
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrReportNotFound   = errors.New("report not found")
+	ErrCategoryNotFound = errors.New("category not found")
 )
 
 type ReportRepository struct {
@@ -18,13 +25,13 @@ func NewReportRepository(db *sql.DB) *ReportRepository {
 	return &ReportRepository{db: db}
 }
 
-func (r *ReportRepository) Create(report *model.Report) error {
+func (r *ReportRepository) CreateInTransaction(tx *sql.Tx, report *model.Report) error {
 	query := `
 		INSERT INTO reports (id, title, description, category_id, location_lat, location_lng, 
 			photo_url, privacy_level, reporter_id, reporter_hash, status, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
-	_, err := r.db.Exec(query,
+	_, err := tx.Exec(query,
 		report.ID,
 		report.Title,
 		report.Description,
@@ -46,19 +53,32 @@ func (r *ReportRepository) GetDB() *sql.DB {
 	return r.db
 }
 
+func (r *ReportRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
 func (r *ReportRepository) FindByID(id uuid.UUID) (*model.Report, error) {
 	query := `
 		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
-			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.vote_score, r.created_at, r.updated_at,
+			r.photo_url, r.privacy_level, r.reporter_id, r.reporter_hash, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			r.assignee_id, a.name, r.assigned_at,
+			r.sla_state, r.ack_due_at, r.resolution_due_at, r.sla_due_at,
 			c.id, c.name, c.department
 		FROM reports r
 		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users a ON r.assignee_id = a.id
 		WHERE r.id = $1
 	`
 	report := &model.Report{Category: &model.Category{}}
 	var lat, lng sql.NullFloat64
 	var photoURL sql.NullString
+	var duplicateOf sql.NullString
+	var statusReason sql.NullString
 	var reporterID sql.NullString
+	var reporterHash sql.NullString
+	var assigneeID, assigneeName sql.NullString
+	var assignedAt sql.NullTime
+	var ackDue, resolutionDue, slaDue sql.NullTime
 
 	err := r.db.QueryRow(query, id).Scan(
 		&report.ID,
@@ -70,17 +90,27 @@ func (r *ReportRepository) FindByID(id uuid.UUID) (*model.Report, error) {
 		&photoURL,
 		&report.PrivacyLevel,
 		&reporterID,
+		&reporterHash,
 		&report.Status,
+		&statusReason,
 		&report.VoteScore,
 		&report.CreatedAt,
 		&report.UpdatedAt,
+		&duplicateOf,
+		&assigneeID,
+		&assigneeName,
+		&assignedAt,
+		&report.SLAState,
+		&ackDue,
+		&resolutionDue,
+		&slaDue,
 		&report.Category.ID,
 		&report.Category.Name,
 		&report.Category.Department,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("report not found")
+			return nil, ErrReportNotFound
 		}
 		return nil, err
 	}
@@ -98,39 +128,105 @@ func (r *ReportRepository) FindByID(id uuid.UUID) (*model.Report, error) {
 		uid, _ := uuid.Parse(reporterID.String)
 		report.ReporterID = &uid
 	}
+	if reporterHash.Valid {
+		report.ReporterHash = &reporterHash.String
+	}
+	if statusReason.Valid {
+		report.StatusReason = &statusReason.String
+	}
+	if duplicateOf.Valid {
+		id, _ := uuid.Parse(duplicateOf.String)
+		report.DuplicateOf = &id
+	}
+	setAssignee(report, assigneeID, assigneeName, assignedAt)
+	setSLADeadlines(report, ackDue, resolutionDue, slaDue)
 
 	return report, nil
 }
 
-func (r *ReportRepository) FindAll(userRole string, department *string) ([]model.Report, error) {
-	var query string
+// FindAll lists one page of public reports, or of every report of a
+// department when one is given, newest first. The filter only applies to
+// department listings.
+func (r *ReportRepository) FindAll(department *string, filter model.ReportFilter, page model.ReportPage) ([]model.Report, int, error) {
+	from := `
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users a ON r.assignee_id = a.id
+	`
 	var args []interface{}
+	order := orderNewest
 
-	if userRole == "warga" {
-		query = `
-			SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
-				r.photo_url, r.privacy_level, r.reporter_id, r.status, r.vote_score, r.created_at, r.updated_at,
-				c.id, c.name, c.department
-			FROM reports r
-			JOIN categories c ON r.category_id = c.id
-			WHERE r.privacy_level = 'public'
-			ORDER BY r.created_at DESC
-		`
-	} else if department != nil {
-		query = `
-			SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
-				r.photo_url, r.privacy_level, r.reporter_id, r.status, r.vote_score, r.created_at, r.updated_at,
-				c.id, c.name, c.department
-			FROM reports r
-			JOIN categories c ON r.category_id = c.id
-			WHERE c.department = $1
-			ORDER BY r.created_at DESC
-		`
-		args = append(args, *department)
+	if department == nil {
+		from += ` WHERE r.privacy_level = 'public' AND r.duplicate_of IS NULL`
 	} else {
-		return nil, fmt.Errorf("unauthorized access")
+		from += ` WHERE c.department = $1`
+		args = append(args, *department)
+
+		if filter.AssigneeID != nil {
+			args = append(args, *filter.AssigneeID)
+			from += fmt.Sprintf(" AND r.assignee_id = $%d", len(args))
+		} else if filter.Unassigned {
+			from += ` AND r.assignee_id IS NULL`
+		}
+		if filter.SLAState != nil {
+			args = append(args, *filter.SLAState)
+			from += fmt.Sprintf(" AND r.sla_state = $%d", len(args))
+		}
+		if filter.SortBySLA {
+			from += ` AND r.sla_due_at IS NOT NULL`
+			order = orderSLADue
+		}
+	}
+
+	total, err := r.count(from, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			r.assignee_id, a.name, r.assigned_at,
+			r.sla_state, r.ack_due_at, r.resolution_due_at, r.sla_due_at,
+			c.id, c.name, c.department
+	`+from, args, page, order)
+
+	reports, err := r.queryAssignedReports(query, args)
+	return reports, total, err
+}
+
+// FindByAssignee lists one page of the open reports assigned to an officer,
+// newest first. Merged reports follow their canonical report and are left
+// out.
+func (r *ReportRepository) FindByAssignee(assigneeID uuid.UUID, page model.ReportPage) ([]model.Report, int, error) {
+	from := `
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users a ON r.assignee_id = a.id
+		WHERE r.assignee_id = $1 AND r.duplicate_of IS NULL AND r.status NOT IN ('completed', 'rejected')
+	`
+	args := []interface{}{assigneeID}
+
+	total, err := r.count(from, args)
+	if err != nil {
+		return nil, 0, err
 	}
 
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			r.assignee_id, a.name, r.assigned_at,
+			r.sla_state, r.ack_due_at, r.resolution_due_at, r.sla_due_at,
+			c.id, c.name, c.department
+	`+from, args, page, orderNewest)
+
+	reports, err := r.queryAssignedReports(query, args)
+	return reports, total, err
+}
+
+// queryAssignedReports runs a department listing built by FindAll or
+// FindByAssignee and scans its rows.
+func (r *ReportRepository) queryAssignedReports(query string, args []interface{}) ([]model.Report, error) {
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -142,7 +238,12 @@ func (r *ReportRepository) FindAll(userRole string, department *string) ([]model
 		report := model.Report{Category: &model.Category{}}
 		var lat, lng sql.NullFloat64
 		var photoURL sql.NullString
+		var duplicateOf sql.NullString
+		var statusReason sql.NullString
 		var reporterID sql.NullString
+		var assigneeID, assigneeName sql.NullString
+		var assignedAt sql.NullTime
+		var ackDue, resolutionDue, slaDue sql.NullTime
 
 		err := rows.Scan(
 			&report.ID,
@@ -155,9 +256,18 @@ func (r *ReportRepository) FindAll(userRole string, department *string) ([]model
 			&report.PrivacyLevel,
 			&reporterID,
 			&report.Status,
+			&statusReason,
 			&report.VoteScore,
 			&report.CreatedAt,
 			&report.UpdatedAt,
+			&duplicateOf,
+			&assigneeID,
+			&assigneeName,
+			&assignedAt,
+			&report.SLAState,
+			&ackDue,
+			&resolutionDue,
+			&slaDue,
 			&report.Category.ID,
 			&report.Category.Name,
 			&report.Category.Department,
@@ -179,27 +289,70 @@ func (r *ReportRepository) FindAll(userRole string, department *string) ([]model
 			uid, _ := uuid.Parse(reporterID.String)
 			report.ReporterID = &uid
 		}
+		if statusReason.Valid {
+			report.StatusReason = &statusReason.String
+		}
+		if duplicateOf.Valid {
+			id, _ := uuid.Parse(duplicateOf.String)
+			report.DuplicateOf = &id
+		}
+		setAssignee(&report, assigneeID, assigneeName, assignedAt)
+		setSLADeadlines(&report, ackDue, resolutionDue, slaDue)
 
 		reports = append(reports, report)
 	}
 
-	return reports, nil
+	return reports, rows.Err()
 }
 
-func (r *ReportRepository) FindByReporterID(reporterID uuid.UUID) ([]model.Report, error) {
-	query := `
-		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
-			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.vote_score, r.created_at, r.updated_at,
-			c.id, c.name, c.department
+func setAssignee(report *model.Report, id, name sql.NullString, at sql.NullTime) {
+	if id.Valid {
+		uid, _ := uuid.Parse(id.String)
+		report.AssigneeID = &uid
+	}
+	if name.Valid {
+		report.AssigneeName = &name.String
+	}
+	if at.Valid {
+		report.AssignedAt = &at.Time
+	}
+}
+
+func setSLADeadlines(report *model.Report, ack, resolution, running sql.NullTime) {
+	if ack.Valid {
+		report.AckDueAt = &ack.Time
+	}
+	if resolution.Valid {
+		report.ResolutionDueAt = &resolution.Time
+	}
+	if running.Valid {
+		report.SLADueAt = &running.Time
+	}
+}
+
+// FindByReporterID lists one page of a user's own reports, newest first.
+func (r *ReportRepository) FindByReporterID(reporterID uuid.UUID, page model.ReportPage) ([]model.Report, int, error) {
+	from := `
 		FROM reports r
 		JOIN categories c ON r.category_id = c.id
 		WHERE r.reporter_id = $1
-		ORDER BY r.created_at DESC
 	`
+	args := []interface{}{reporterID}
 
-	rows, err := r.db.Query(query, reporterID)
+	total, err := r.count(from, args)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department
+	`+from, args, page, orderNewest)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -208,6 +361,8 @@ func (r *ReportRepository) FindByReporterID(reporterID uuid.UUID) ([]model.Repor
 		report := model.Report{Category: &model.Category{}}
 		var lat, lng sql.NullFloat64
 		var photoURL sql.NullString
+		var duplicateOf sql.NullString
+		var statusReason sql.NullString
 		var reporterIDNull sql.NullString
 
 		err := rows.Scan(
@@ -221,15 +376,17 @@ func (r *ReportRepository) FindByReporterID(reporterID uuid.UUID) ([]model.Repor
 			&report.PrivacyLevel,
 			&reporterIDNull,
 			&report.Status,
+			&statusReason,
 			&report.VoteScore,
 			&report.CreatedAt,
 			&report.UpdatedAt,
+			&duplicateOf,
 			&report.Category.ID,
 			&report.Category.Name,
 			&report.Category.Department,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		if lat.Valid {
@@ -245,60 +402,161 @@ func (r *ReportRepository) FindByReporterID(reporterID uuid.UUID) ([]model.Repor
 			uid, _ := uuid.Parse(reporterIDNull.String)
 			report.ReporterID = &uid
 		}
+		if statusReason.Valid {
+			report.StatusReason = &statusReason.String
+		}
+		if duplicateOf.Valid {
+			id, _ := uuid.Parse(duplicateOf.String)
+			report.DuplicateOf = &id
+		}
 
 		reports = append(reports, report)
 	}
 
-	return reports, nil
+	return reports, total, rows.Err()
 }
 
-func (r *ReportRepository) UpdateStatus(id uuid.UUID, status model.ReportStatus) error {
-	query := `UPDATE reports SET status = $1, updated_at = NOW() WHERE id = $2`
-	result, err := r.db.Exec(query, status, id)
+// UpdateStatus moves a report and every report merged into it from one
+// status to another, recording the change in each report's status history.
+// It returns false, changing nothing, when the report is no longer in the
+// from status, e.g. because another admin changed it first.
+func (r *ReportRepository) UpdateStatus(id uuid.UUID, from, to model.ReportStatus, reason *string, actor model.StatusActor) (bool, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// acknowledged_at and closed_at let the SLA job judge deadlines by when
+	// the status changed rather than by when it happens to run
+	query := `
+		UPDATE reports SET status = $1, status_reason = $2, updated_at = NOW(),
+			acknowledged_at = CASE WHEN $4::text = 'pending' THEN COALESCE(acknowledged_at, NOW()) ELSE acknowledged_at END,
+			closed_at = CASE WHEN $1::text IN ('completed', 'rejected') THEN NOW() END
+		WHERE id = $3 AND status = $4
+	`
+	result, err := tx.Exec(query, to, reason, id, from)
+	if err != nil {
+		return false, err
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return false, err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("report not found")
+		return false, nil
 	}
 
-	return nil
+	// Written before the duplicates are updated so their old status is still
+	// there to copy
+	_, err = tx.Exec(`
+		INSERT INTO report_status_history (report_id, actor_id, actor_name, old_status, new_status, note, created_at)
+		SELECT id, $2, $3, CASE WHEN id = $1 THEN $4 ELSE status END, $5, $6, NOW()
+		FROM reports
+		WHERE id = $1 OR duplicate_of = $1
+	`, id, actor.ID, actor.Name, from, to, reason)
+	if err != nil {
+		return false, err
+	}
+
+	query = `UPDATE reports SET status = $1, status_reason = $2, updated_at = NOW() WHERE duplicate_of = $3`
+	if _, err := tx.Exec(query, to, reason, id); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// FindStatusHistory returns a report's status changes, oldest first.
+func (r *ReportRepository) FindStatusHistory(reportID uuid.UUID) ([]model.StatusHistoryEntry, error) {
+	query := `
+		SELECT id, report_id, actor_id, actor_name, old_status, new_status, note, created_at
+		FROM report_status_history
+		WHERE report_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(query, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.StatusHistoryEntry{}
+	for rows.Next() {
+		var entry model.StatusHistoryEntry
+		var actorID, actorName, note sql.NullString
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ReportID,
+			&actorID,
+			&actorName,
+			&entry.OldStatus,
+			&entry.NewStatus,
+			&note,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if actorID.Valid {
+			id, _ := uuid.Parse(actorID.String)
+			entry.ActorID = &id
+		}
+		if actorName.Valid {
+			entry.ActorName = &actorName.String
+		}
+		if note.Valid {
+			entry.Note = &note.String
+		}
+
+		history = append(history, entry)
+	}
+
+	return history, rows.Err()
 }
 
 func (r *ReportRepository) GetCategoryByID(id int) (*model.Category, error) {
-	query := `SELECT id, name, department FROM categories WHERE id = $1`
+	query := `SELECT id, name, department, ack_target_hours, resolution_target_hours FROM categories WHERE id = $1`
 	cat := &model.Category{}
-	err := r.db.QueryRow(query, id).Scan(&cat.ID, &cat.Name, &cat.Department)
+	var ackHours, resolutionHours sql.NullInt32
+	err := r.db.QueryRow(query, id).Scan(&cat.ID, &cat.Name, &cat.Department, &ackHours, &resolutionHours)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("category not found")
+			return nil, ErrCategoryNotFound
 		}
 		return nil, err
 	}
+	setSLATargets(cat, ackHours, resolutionHours)
 	return cat, nil
 }
 
-func (r *ReportRepository) GetPublicReports() ([]model.Report, error) {
-	query := `
-		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
-			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.vote_score, r.created_at, r.updated_at,
-			c.id, c.name, c.department,
-			u.name as reporter_name
+// GetPublicReports lists one page of the public feed, highest voted first.
+func (r *ReportRepository) GetPublicReports(page model.ReportPage) ([]model.Report, int, error) {
+	from := `
 		FROM reports r
 		JOIN categories c ON r.category_id = c.id
 		LEFT JOIN users u ON r.reporter_id = u.id
-		WHERE r.privacy_level = 'public'
-		ORDER BY r.vote_score DESC, r.created_at DESC
+		WHERE r.privacy_level = 'public' AND r.duplicate_of IS NULL
 	`
 
-	rows, err := r.db.Query(query)
+	total, err := r.count(from, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			u.name as reporter_name
+	`+from, nil, page, orderVotes)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -307,6 +565,8 @@ func (r *ReportRepository) GetPublicReports() ([]model.Report, error) {
 		report := model.Report{Category: &model.Category{}}
 		var lat, lng sql.NullFloat64
 		var photoURL sql.NullString
+		var duplicateOf sql.NullString
+		var statusReason sql.NullString
 		var reporterID sql.NullString
 		var reporterName sql.NullString
 
@@ -321,16 +581,18 @@ func (r *ReportRepository) GetPublicReports() ([]model.Report, error) {
 			&report.PrivacyLevel,
 			&reporterID,
 			&report.Status,
+			&statusReason,
 			&report.VoteScore,
 			&report.CreatedAt,
 			&report.UpdatedAt,
+			&duplicateOf,
 			&report.Category.ID,
 			&report.Category.Name,
 			&report.Category.Department,
 			&reporterName,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		if lat.Valid {
@@ -349,11 +611,18 @@ func (r *ReportRepository) GetPublicReports() ([]model.Report, error) {
 		if reporterName.Valid {
 			report.ReporterName = &reporterName.String
 		}
+		if statusReason.Valid {
+			report.StatusReason = &statusReason.String
+		}
+		if duplicateOf.Valid {
+			id, _ := uuid.Parse(duplicateOf.String)
+			report.DuplicateOf = &id
+		}
 
 		reports = append(reports, report)
 	}
 
-	return reports, nil
+	return reports, total, rows.Err()
 }
 
 func (r *ReportRepository) Update(id uuid.UUID, title, description *string) error {
@@ -385,14 +654,14 @@ func (r *ReportRepository) Update(id uuid.UUID, title, description *string) erro
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("report not found")
+		return ErrReportNotFound
 	}
 
 	return nil
 }
 
 func (r *ReportRepository) GetAllCategories() ([]model.Category, error) {
-	query := `SELECT id, name, department FROM categories ORDER BY department, name`
+	query := `SELECT id, name, department, ack_target_hours, resolution_target_hours FROM categories ORDER BY department, name`
 	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -402,89 +671,525 @@ func (r *ReportRepository) GetAllCategories() ([]model.Category, error) {
 	var categories []model.Category
 	for rows.Next() {
 		var cat model.Category
-		if err := rows.Scan(&cat.ID, &cat.Name, &cat.Department); err != nil {
+		var ackHours, resolutionHours sql.NullInt32
+		if err := rows.Scan(&cat.ID, &cat.Name, &cat.Department, &ackHours, &resolutionHours); err != nil {
 			return nil, err
 		}
+		setSLATargets(&cat, ackHours, resolutionHours)
 		categories = append(categories, cat)
 	}
 	return categories, nil
 }
 
-func (r *ReportRepository) SearchPublicReports(search string, categoryID *int) ([]model.Report, error) {
-	query := `
-		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
-			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.vote_score, r.created_at, r.updated_at,
-			c.id, c.name, c.department,
-			u.name as reporter_name
+// SearchPublicReports lists one page of public reports matching a search
+// query and/or a category. The query takes web search syntax: "quoted
+// phrases", -excluded terms and OR. Text searches are ordered by relevance,
+// category-only listings by votes.
+func (r *ReportRepository) SearchPublicReports(search string, categoryID *int, page model.ReportPage) ([]model.Report, int, error) {
+	from := `
 		FROM reports r
 		JOIN categories c ON r.category_id = c.id
 		LEFT JOIN users u ON r.reporter_id = u.id
-		WHERE r.privacy_level = 'public'
 	`
+	where := ` WHERE r.privacy_level = 'public' AND r.duplicate_of IS NULL`
 	args := []interface{}{}
-	argIndex := 1
+	order := orderVotes
+	columns := noSearchColumns
 
 	if search != "" {
-		query += fmt.Sprintf(" AND (LOWER(r.title) LIKE LOWER($%d) OR LOWER(r.description) LIKE LOWER($%d))", argIndex, argIndex)
-		args = append(args, "%"+search+"%")
-		argIndex++
+		args = append(args, search)
+		from += fmt.Sprintf(" CROSS JOIN websearch_to_tsquery('cityconnect', $%d) q", len(args))
+		where += " AND r.search_vector @@ q"
+		order = orderRank
+		columns = searchColumns
 	}
 
 	if categoryID != nil && *categoryID > 0 {
-		query += fmt.Sprintf(" AND r.category_id = $%d", argIndex)
 		args = append(args, *categoryID)
-		argIndex++
+		where += fmt.Sprintf(" AND r.category_id = $%d", len(args))
 	}
 
-	query += " ORDER BY r.vote_score DESC, r.created_at DESC"
+	total, err := r.count(from+where, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			u.name as reporter_name
+	`+columns+from+where, args, page, order)
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	return r.scanReportsWithReporterName(rows)
+	reports, err := r.scanListResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
 }
 
-func (r *ReportRepository) SearchMyReports(reporterID uuid.UUID, search string, categoryID *int) ([]model.Report, error) {
-	query := `
-		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
-			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.vote_score, r.created_at, r.updated_at,
-			c.id, c.name, c.department
+// SearchMyReports is SearchPublicReports over a user's own reports, with
+// category-only listings ordered newest first.
+func (r *ReportRepository) SearchMyReports(reporterID uuid.UUID, search string, categoryID *int, page model.ReportPage) ([]model.Report, int, error) {
+	from := `
 		FROM reports r
 		JOIN categories c ON r.category_id = c.id
-		WHERE r.reporter_id = $1
 	`
+	where := ` WHERE r.reporter_id = $1`
 	args := []interface{}{reporterID}
-	argIndex := 2
+	order := orderNewest
+	columns := noSearchColumns
 
 	if search != "" {
-		query += fmt.Sprintf(" AND (LOWER(r.title) LIKE LOWER($%d) OR LOWER(r.description) LIKE LOWER($%d))", argIndex, argIndex)
-		args = append(args, "%"+search+"%")
-		argIndex++
+		args = append(args, search)
+		from += fmt.Sprintf(" CROSS JOIN websearch_to_tsquery('cityconnect', $%d) q", len(args))
+		where += " AND r.search_vector @@ q"
+		order = orderRank
+		columns = searchColumns
 	}
 
 	if categoryID != nil && *categoryID > 0 {
-		query += fmt.Sprintf(" AND r.category_id = $%d", argIndex)
 		args = append(args, *categoryID)
-		argIndex++
+		where += fmt.Sprintf(" AND r.category_id = $%d", len(args))
+	}
+
+	total, err := r.count(from+where, args)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	query += " ORDER BY r.created_at DESC"
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			NULL as reporter_name
+	`+columns+from+where, args, page, order)
 
 	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reports, err := r.scanListResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// FindNearby lists one page of public reports within radius metres of a
+// point, nearest first. The bounding box of the circle is passed in as well
+// so the location index can narrow the rows before distances are computed.
+func (r *ReportRepository) FindNearby(lat, lng, radius float64, box model.BoundingBox, page model.ReportPage) ([]model.Report, int, error) {
+	from := `
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users u ON r.reporter_id = u.id
+		CROSS JOIN (SELECT $1::double precision AS lat, $2::double precision AS lng) p
+		WHERE r.privacy_level = 'public' AND r.duplicate_of IS NULL
+			AND r.location_lat BETWEEN $3 AND $4
+			AND r.location_lng BETWEEN $5 AND $6
+			AND ` + reportDistance + ` <= $7
+	`
+	args := []interface{}{lat, lng, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng, radius}
+
+	total, err := r.count(from, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			u.name as reporter_name
+	`+distanceColumns+from, args, page, orderDistance)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reports, err := r.scanListResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// FindWithin lists one page of public reports inside a bounding box, highest
+// voted first.
+func (r *ReportRepository) FindWithin(box model.BoundingBox, page model.ReportPage) ([]model.Report, int, error) {
+	from := `
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users u ON r.reporter_id = u.id
+		WHERE r.privacy_level = 'public' AND r.duplicate_of IS NULL
+			AND r.location_lat BETWEEN $1 AND $2
+			AND r.location_lng BETWEEN $3 AND $4
+	`
+	args := []interface{}{box.MinLat, box.MaxLat, box.MinLng, box.MaxLng}
+
+	total, err := r.count(from, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := pageQuery(`
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			u.name as reporter_name
+	`+from, args, page, orderVotes)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reports, err := r.scanReportsWithReporterName(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// FindDuplicateCandidates lists open, unmerged reports of the same
+// department as the described report whose title and description are
+// similar enough to it, most similar first. Reports with a location only
+// match reports within the radius.
+func (r *ReportRepository) FindDuplicateCandidates(q model.DuplicateQuery) ([]model.Report, error) {
+	from := `
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users u ON r.reporter_id = u.id
+		CROSS JOIN (SELECT $1::text AS title, $2::text AS description) d
+	`
+	where := `
+		WHERE c.department = (SELECT department FROM categories WHERE id = $3)
+			AND r.id <> $4
+			AND r.duplicate_of IS NULL
+			AND r.status IN ('pending', 'accepted', 'in_progress')
+			AND r.created_at >= $5
+			AND ` + duplicateSimilarity + ` >= $6
+	`
+	args := []interface{}{q.Title, q.Description, q.CategoryID, q.ReportID, q.Since, q.MinSimilarity}
+	distance := "NULL::double precision"
+
+	if q.Lat != nil && q.Lng != nil {
+		from += ` CROSS JOIN (SELECT $7::double precision AS lat, $8::double precision AS lng) p`
+		where += `
+			AND r.location_lat BETWEEN $9 AND $10
+			AND r.location_lng BETWEEN $11 AND $12
+			AND ` + reportDistance + ` <= $13
+		`
+		args = append(args, *q.Lat, *q.Lng, q.Box.MinLat, q.Box.MaxLat, q.Box.MinLng, q.Box.MaxLng, q.RadiusMeters)
+		distance = reportDistance
+	}
+
+	if q.VisibleTo != nil {
+		args = append(args, *q.VisibleTo)
+		where += fmt.Sprintf(" AND (r.privacy_level = 'public' OR r.reporter_id = $%d)", len(args))
+	}
+
+	args = append(args, q.Limit)
+	query := `
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			u.name as reporter_name,
+			NULL::real, NULL, NULL,
+			` + distance + `,
+			` + duplicateSimilarity + ` AS score
+	` + from + where + fmt.Sprintf(" ORDER BY score DESC, r.created_at DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanListResults(rows)
+}
+
+// FindDuplicates returns the reports merged into a canonical report, in the
+// order they were merged.
+func (r *ReportRepository) FindDuplicates(canonicalID uuid.UUID) ([]model.Report, error) {
+	query := `
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			u.name as reporter_name
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users u ON r.reporter_id = u.id
+		WHERE r.duplicate_of = $1
+		ORDER BY r.merged_at, r.created_at
+	`
+
+	rows, err := r.db.Query(query, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanReportsWithReporterName(rows)
+}
+
+// Merge links duplicates to a canonical report. Reports merged into one of
+// the duplicates earlier follow it, and every merged report takes over the
+// canonical status. Votes move to the canonical report; a user who voted on
+// several of them keeps a single vote, their canonical one if they cast it,
+// their latest otherwise.
+func (r *ReportRepository) Merge(canonicalID uuid.UUID, duplicateIDs []uuid.UUID, actor model.StatusActor) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ids := make([]string, len(duplicateIDs))
+	for i, id := range duplicateIDs {
+		ids[i] = id.String()
+	}
+
+	var status model.ReportStatus
+	var statusReason sql.NullString
+	err = tx.QueryRow(`SELECT status, status_reason FROM reports WHERE id = $1 AND duplicate_of IS NULL FOR UPDATE`, canonicalID).Scan(&status, &statusReason)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrReportNotFound
+		}
+		return err
+	}
+
+	// Merged reports whose status changes get a history entry like any other
+	// status change
+	_, err = tx.Exec(`
+		INSERT INTO report_status_history (report_id, actor_id, actor_name, old_status, new_status, note, created_at)
+		SELECT id, $1, $2, status, $3, $4, NOW()
+		FROM reports
+		WHERE (id = ANY($5::uuid[]) OR duplicate_of = ANY($5::uuid[])) AND status <> $3
+	`, actor.ID, actor.Name, status, fmt.Sprintf("Digabungkan ke laporan %s", canonicalID), pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE reports
+		SET duplicate_of = $1, status = $2, status_reason = $3, merged_at = COALESCE(merged_at, NOW()), updated_at = NOW()
+		WHERE id = ANY($4::uuid[]) OR duplicate_of = ANY($4::uuid[])
+	`, canonicalID, status, statusReason, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrReportNotFound
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO report_votes (id, report_id, user_id, vote_type, created_at)
+		SELECT DISTINCT ON (v.user_id) gen_random_uuid(), $1, v.user_id, v.vote_type, v.created_at
+		FROM report_votes v
+		WHERE v.report_id = ANY($2::uuid[])
+			AND NOT EXISTS (
+				SELECT 1 FROM report_votes cv WHERE cv.report_id = $1 AND cv.user_id = v.user_id
+			)
+		ORDER BY v.user_id, v.created_at DESC
+	`, canonicalID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM report_votes WHERE report_id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE reports SET vote_score = 0 WHERE id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reports SET vote_score = (
+			SELECT COALESCE(SUM(CASE WHEN vote_type = 'upvote' THEN 1 ELSE -1 END), 0)
+			FROM report_votes WHERE report_id = $1
+		), updated_at = NOW()
+		WHERE id = $1
+	`, canonicalID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindByReporter returns every report filed by a user: reports that carry
+// their id and anonymous reports that carry their reporter hash.
+func (r *ReportRepository) FindByReporter(reporterID uuid.UUID, reporterHash string) ([]model.Report, error) {
+	query := `
+		SELECT r.id, r.title, r.description, r.category_id, r.location_lat, r.location_lng,
+			r.photo_url, r.privacy_level, r.reporter_id, r.status, r.status_reason, r.vote_score, r.created_at, r.updated_at, r.duplicate_of,
+			c.id, c.name, c.department,
+			u.name as reporter_name
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		LEFT JOIN users u ON r.reporter_id = u.id
+		WHERE r.reporter_id = $1 OR r.reporter_hash = $2
+		ORDER BY r.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, reporterID, reporterHash)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return r.scanReportsWithReporterName(rows)
+}
+
+// AnonymiseReporter detaches a user's reports from their account, keeping
+// only the reporter hash anonymous reports already use. The reports
+// themselves stay for the audit trail.
+func (r *ReportRepository) AnonymiseReporter(reporterID uuid.UUID, reporterHash string) (int64, error) {
+	query := `
+		UPDATE reports SET reporter_id = NULL, reporter_hash = $2, updated_at = NOW()
+		WHERE reporter_id = $1
+	`
+	result, err := r.db.Exec(query, reporterID, reporterHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// count returns how many reports match a FROM ... WHERE clause.
+func (r *ReportRepository) count(from string, args []interface{}) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) `+from, args...).Scan(&total)
+	return total, err
+}
+
+// reportOrder is the leading sort key of a listing. created_at and id always
+// follow it, so the order is total and consecutive pages never overlap or
+// skip a report.
+type reportOrder int
+
+const (
+	orderNewest reportOrder = iota
+	orderVotes
+	orderRank
+	orderDistance
+	orderSLADue
+)
+
+const (
+	// searchRank scores a report against the tsquery q that text searches
+	// join in.
+	searchRank = "ts_rank(r.search_vector, q)"
+
+	// reportDistance is the great-circle distance in metres between a report
+	// and the point p that nearby listings join in.
+	reportDistance = `6371000 * 2 * ASIN(SQRT(
+			POWER(SIN(RADIANS(r.location_lat - p.lat) / 2), 2) +
+			COS(RADIANS(p.lat)) * COS(RADIANS(r.location_lat)) * POWER(SIN(RADIANS(r.location_lng - p.lng) / 2), 2)
+		))`
+)
+
+// Listings scanned by scanListResults select one of these column sets after
+// reporter_name: relevance and highlights for text searches, the distance for
+// nearby listings, NULLs otherwise. Duplicate suggestions build their own.
+const (
+	searchColumns = `,
+			` + searchRank + `,
+			ts_headline('cityconnect', r.title, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+			ts_headline('cityconnect', r.description, q, 'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2'),
+			NULL::double precision, NULL::real
+	`
+	distanceColumns = `, NULL::real, NULL, NULL,
+			` + reportDistance + `,
+			NULL::real
+	`
+	noSearchColumns = `, NULL::real, NULL, NULL, NULL::double precision, NULL::real `
+)
+
+// duplicateSimilarity scores a report against the title and description of
+// the report d that duplicate suggestions join in. Titles weigh more, since
+// descriptions of the same problem vary a lot more in wording.
+const duplicateSimilarity = `(0.6 * similarity(r.title, d.title) + 0.4 * similarity(r.description, d.description))::real`
+
+// pageQuery appends the keyset condition, ordering and limit for one page to
+// a query that already has a WHERE clause.
+func pageQuery(query string, args []interface{}, page model.ReportPage, order reportOrder) (string, []interface{}) {
+	after := page.After
+	if after == nil {
+		after = &model.ReportCursor{}
+	}
+
+	columns := []string{"r.created_at", "r.id"}
+	values := []interface{}{after.CreatedAt, after.ID}
+	direction, comparison := "DESC", "<"
+
+	switch order {
+	case orderVotes:
+		columns = append([]string{"r.vote_score"}, columns...)
+		values = append([]interface{}{after.VoteScore}, values...)
+	case orderRank:
+		columns = append([]string{searchRank}, columns...)
+		values = append([]interface{}{after.Rank}, values...)
+	case orderDistance:
+		// Nearest first; the id alone breaks ties in distance
+		columns = []string{reportDistance, "r.id"}
+		values = []interface{}{after.Distance, after.ID}
+		direction, comparison = "ASC", ">"
+	case orderSLADue:
+		// Earliest deadline first, so overdue reports lead
+		columns = []string{"r.sla_due_at", "r.id"}
+		values = []interface{}{after.SLADueAt, after.ID}
+		direction, comparison = "ASC", ">"
+	}
+
+	if page.After != nil {
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		query += fmt.Sprintf(" AND (%s) %s (%s)", strings.Join(columns, ", "), comparison, strings.Join(placeholders, ", "))
+	}
+
+	query += " ORDER BY " + strings.Join(columns, " "+direction+", ") + " " + direction
+
+	args = append(args, page.Limit)
+	query += fmt.Sprintf(" LIMIT $%d", len(args))
+
+	return query, args
+}
+
+func (r *ReportRepository) scanReportsWithReporterName(rows *sql.Rows) ([]model.Report, error) {
 	var reports []model.Report
 	for rows.Next() {
 		report := model.Report{Category: &model.Category{}}
 		var lat, lng sql.NullFloat64
 		var photoURL sql.NullString
-		var reporterIDNull sql.NullString
+		var duplicateOf sql.NullString
+		var statusReason sql.NullString
+		var reporterID sql.NullString
+		var reporterName sql.NullString
 
 		err := rows.Scan(
 			&report.ID,
@@ -495,14 +1200,17 @@ func (r *ReportRepository) SearchMyReports(reporterID uuid.UUID, search string,
 			&lng,
 			&photoURL,
 			&report.PrivacyLevel,
-			&reporterIDNull,
+			&reporterID,
 			&report.Status,
+			&statusReason,
 			&report.VoteScore,
 			&report.CreatedAt,
 			&report.UpdatedAt,
+			&duplicateOf,
 			&report.Category.ID,
 			&report.Category.Name,
 			&report.Category.Department,
+			&reporterName,
 		)
 		if err != nil {
 			return nil, err
@@ -517,10 +1225,20 @@ func (r *ReportRepository) SearchMyReports(reporterID uuid.UUID, search string,
 		if photoURL.Valid {
 			report.PhotoURL = &photoURL.String
 		}
-		if reporterIDNull.Valid {
-			uid, _ := uuid.Parse(reporterIDNull.String)
+		if reporterID.Valid {
+			uid, _ := uuid.Parse(reporterID.String)
 			report.ReporterID = &uid
 		}
+		if reporterName.Valid {
+			report.ReporterName = &reporterName.String
+		}
+		if statusReason.Valid {
+			report.StatusReason = &statusReason.String
+		}
+		if duplicateOf.Valid {
+			id, _ := uuid.Parse(duplicateOf.String)
+			report.DuplicateOf = &id
+		}
 
 		reports = append(reports, report)
 	}
@@ -528,14 +1246,23 @@ func (r *ReportRepository) SearchMyReports(reporterID uuid.UUID, search string,
 	return reports, nil
 }
 
-func (r *ReportRepository) scanReportsWithReporterName(rows *sql.Rows) ([]model.Report, error) {
+// scanListResults scans rows laid out like scanReportsWithReporterName
+// followed by searchColumns, distanceColumns, noSearchColumns or the columns
+// of FindDuplicateCandidates.
+func (r *ReportRepository) scanListResults(rows *sql.Rows) ([]model.Report, error) {
 	var reports []model.Report
 	for rows.Next() {
 		report := model.Report{Category: &model.Category{}}
 		var lat, lng sql.NullFloat64
 		var photoURL sql.NullString
+		var duplicateOf sql.NullString
+		var statusReason sql.NullString
 		var reporterID sql.NullString
 		var reporterName sql.NullString
+		var rank sql.NullFloat64
+		var titleHighlight, descriptionHighlight sql.NullString
+		var distance sql.NullFloat64
+		var similarity sql.NullFloat64
 
 		err := rows.Scan(
 			&report.ID,
@@ -548,13 +1275,20 @@ func (r *ReportRepository) scanReportsWithReporterName(rows *sql.Rows) ([]model.
 			&report.PrivacyLevel,
 			&reporterID,
 			&report.Status,
+			&statusReason,
 			&report.VoteScore,
 			&report.CreatedAt,
 			&report.UpdatedAt,
+			&duplicateOf,
 			&report.Category.ID,
 			&report.Category.Name,
 			&report.Category.Department,
 			&reporterName,
+			&rank,
+			&titleHighlight,
+			&descriptionHighlight,
+			&distance,
+			&similarity,
 		)
 		if err != nil {
 			return nil, err
@@ -576,26 +1310,57 @@ func (r *ReportRepository) scanReportsWithReporterName(rows *sql.Rows) ([]model.
 		if reporterName.Valid {
 			report.ReporterName = &reporterName.String
 		}
+		if rank.Valid {
+			score := float32(rank.Float64)
+			report.Rank = &score
+			report.Highlight = &model.ReportHighlight{
+				Title:       titleHighlight.String,
+				Description: descriptionHighlight.String,
+			}
+		}
+		if distance.Valid {
+			report.Distance = &distance.Float64
+		}
+		if similarity.Valid {
+			score := float32(similarity.Float64)
+			report.Similarity = &score
+		}
+		if statusReason.Valid {
+			report.StatusReason = &statusReason.String
+		}
+		if duplicateOf.Valid {
+			id, _ := uuid.Parse(duplicateOf.String)
+			report.DuplicateOf = &id
+		}
 
 		reports = append(reports, report)
 	}
 
-	return reports, nil
+	return reports, rows.Err()
 }
 
 func (r *ReportRepository) FindCategoryByName(name string) (*model.Category, error) {
-	query := `SELECT id, name, department FROM categories WHERE LOWER(name) = LOWER($1)`
+	query := `SELECT id, name, department, ack_target_hours, resolution_target_hours FROM categories WHERE LOWER(name) = LOWER($1)`
 	cat := &model.Category{}
-	err := r.db.QueryRow(query, name).Scan(&cat.ID, &cat.Name, &cat.Department)
+	var ackHours, resolutionHours sql.NullInt32
+	err := r.db.QueryRow(query, name).Scan(&cat.ID, &cat.Name, &cat.Department, &ackHours, &resolutionHours)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
+	setSLATargets(cat, ackHours, resolutionHours)
 	return cat, nil
 }
 
+func (r *ReportRepository) DepartmentExists(code string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM departments WHERE code = $1)`
+	var exists bool
+	err := r.db.QueryRow(query, code).Scan(&exists)
+	return exists, err
+}
+
 func (r *ReportRepository) CreateCategory(name, department string) (*model.Category, error) {
 	query := `INSERT INTO categories (name, department) VALUES ($1, $2) RETURNING id`
 	var id int
@@ -605,3 +1370,33 @@ func (r *ReportRepository) CreateCategory(name, department string) (*model.Categ
 	}
 	return &model.Category{ID: id, Name: name, Department: department}, nil
 }
+
+// UpdateCategorySLA replaces a category's SLA targets. Reports that already
+// have due dates keep them.
+func (r *ReportRepository) UpdateCategorySLA(id int, ackHours, resolutionHours *int) error {
+	query := `UPDATE categories SET ack_target_hours = $1, resolution_target_hours = $2 WHERE id = $3`
+	result, err := r.db.Exec(query, ackHours, resolutionHours, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+func setSLATargets(cat *model.Category, ackHours, resolutionHours sql.NullInt32) {
+	if ackHours.Valid {
+		hours := int(ackHours.Int32)
+		cat.AckTargetHours = &hours
+	}
+	if resolutionHours.Valid {
+		hours := int(resolutionHours.Int32)
+		cat.ResolutionTargetHours = &hours
+	}
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"report-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// SLARepository keeps the SLA columns of reports for the SLA job. Merged
+// reports follow their canonical report and are skipped throughout.
+type SLARepository struct {
+	db *sql.DB
+}
+
+func NewSLARepository(db *sql.DB) *SLARepository {
+	return &SLARepository{db: db}
+}
+
+func (r *SLARepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+// SetDueDates gives open reports without deadlines the ones their category
+// targets call for, counted from when the report was filed.
+func (r *SLARepository) SetDueDates() (int64, error) {
+	query := `
+		UPDATE reports r SET
+			ack_due_at = r.created_at + c.ack_target_hours * INTERVAL '1 hour',
+			resolution_due_at = r.created_at + c.resolution_target_hours * INTERVAL '1 hour'
+		FROM categories c
+		WHERE r.category_id = c.id
+			AND r.ack_due_at IS NULL AND r.resolution_due_at IS NULL
+			AND (c.ack_target_hours IS NOT NULL OR c.resolution_target_hours IS NOT NULL)
+			AND r.closed_at IS NULL
+			AND r.duplicate_of IS NULL
+	`
+	result, err := r.db.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// FindUnescalatedBreaches lists missed deadlines not yet escalated, oldest
+// first. A deadline is missed when the report was acknowledged, or closed,
+// only after it, or not yet when it passed; a report that changed status late
+// between two runs is still caught.
+func (r *SLARepository) FindUnescalatedBreaches(limit int) ([]model.SLABreach, error) {
+	query := `
+		SELECT r.id, r.title, c.department, r.assignee_id, 'acknowledgement', r.ack_due_at
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		WHERE r.duplicate_of IS NULL AND r.ack_breached_at IS NULL
+			AND r.ack_due_at <= NOW()
+			AND (r.acknowledged_at IS NULL OR r.acknowledged_at > r.ack_due_at)
+		UNION ALL
+		SELECT r.id, r.title, c.department, r.assignee_id, 'resolution', r.resolution_due_at
+		FROM reports r
+		JOIN categories c ON r.category_id = c.id
+		WHERE r.duplicate_of IS NULL AND r.resolution_breached_at IS NULL
+			AND r.resolution_due_at <= NOW()
+			AND (r.closed_at IS NULL OR r.closed_at > r.resolution_due_at)
+		ORDER BY 6, 1
+		LIMIT $1
+	`
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breaches []model.SLABreach
+	for rows.Next() {
+		var breach model.SLABreach
+		var assigneeID sql.NullString
+
+		err := rows.Scan(
+			&breach.ReportID,
+			&breach.ReportTitle,
+			&breach.Department,
+			&assigneeID,
+			&breach.Kind,
+			&breach.DueAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if assigneeID.Valid {
+			id, _ := uuid.Parse(assigneeID.String)
+			breach.AssigneeID = &id
+		}
+
+		breaches = append(breaches, breach)
+	}
+
+	return breaches, rows.Err()
+}
+
+// MarkBreachedInTransaction records that a deadline was missed. It returns
+// false when the breach was already recorded, e.g. by another instance.
+func (r *SLARepository) MarkBreachedInTransaction(tx *sql.Tx, reportID uuid.UUID, kind model.SLAKind) (bool, error) {
+	var query string
+	switch kind {
+	case model.SLAAcknowledgement:
+		query = `UPDATE reports SET ack_breached_at = NOW() WHERE id = $1 AND ack_breached_at IS NULL`
+	case model.SLAResolution:
+		query = `UPDATE reports SET resolution_breached_at = NOW() WHERE id = $1 AND resolution_breached_at IS NULL`
+	default:
+		return false, fmt.Errorf("unknown SLA kind %q", kind)
+	}
+
+	result, err := tx.Exec(query, reportID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// UpdateStates recomputes the running deadline and SLA state of reports
+// whose state can still change: open ones, and closed ones not yet settled as
+// met or breached. Only rows that actually change are written. Until a report
+// is acknowledged the earlier of its two deadlines runs, afterwards the
+// resolution deadline. A report is at risk once atRiskRatio of the time from
+// filing to that deadline has passed. Deadlines are judged by when the report
+// was acknowledged or closed, so closing late never counts as met.
+func (r *SLARepository) UpdateStates(atRiskRatio float64) (int64, error) {
+	query := `
+		WITH running AS (
+			SELECT r.id, r.created_at, r.closed_at IS NOT NULL AS closed,
+				CASE
+					WHEN r.closed_at IS NOT NULL THEN NULL
+					WHEN r.acknowledged_at IS NULL THEN LEAST(r.ack_due_at, r.resolution_due_at)
+					ELSE r.resolution_due_at
+				END AS due,
+				r.ack_breached_at IS NOT NULL OR r.resolution_breached_at IS NOT NULL
+					OR r.ack_due_at < COALESCE(r.acknowledged_at, NOW())
+					OR r.resolution_due_at < COALESCE(r.closed_at, NOW()) AS missed
+			FROM reports r
+			WHERE r.duplicate_of IS NULL
+				AND (r.ack_due_at IS NOT NULL OR r.resolution_due_at IS NOT NULL)
+				AND (r.closed_at IS NULL OR r.sla_state NOT IN ('met', 'breached'))
+		), computed AS (
+			SELECT id, due,
+				CASE
+					WHEN missed THEN 'breached'
+					WHEN closed THEN 'met'
+					WHEN due IS NULL THEN 'none'
+					WHEN NOW() >= created_at + (due - created_at) * $1::float8 THEN 'at_risk'
+					ELSE 'on_track'
+				END AS state
+			FROM running
+		)
+		UPDATE reports r SET sla_due_at = computed.due, sla_state = computed.state
+		FROM computed
+		WHERE r.id = computed.id
+			AND (r.sla_due_at IS DISTINCT FROM computed.due OR r.sla_state <> computed.state)
+	`
+	result, err := r.db.Exec(query, atRiskRatio)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
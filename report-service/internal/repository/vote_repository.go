@@ -2,7 +2,7 @@ package repository
 
 import (
 	"database/sql"
-	"fmt"
+	"errors"
 	"time"
 
 	"report-service/internal/model"
@@ -10,6 +10,11 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrVoteNotFound   = errors.New("vote not found")
+	ErrNoVoteToRemove = errors.New("no vote to remove")
+)
+
 type VoteRepository struct {
 	db *sql.DB
 }
@@ -41,6 +46,37 @@ func (r *VoteRepository) GetVote(reportID, userID uuid.UUID) (*model.ReportVote,
 	return vote, nil
 }
 
+func (r *VoteRepository) FindByUserID(userID uuid.UUID) ([]model.ReportVote, error) {
+	query := `
+		SELECT id, report_id, user_id, vote_type, created_at
+		FROM report_votes
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	votes := []model.ReportVote{}
+	for rows.Next() {
+		var vote model.ReportVote
+		err := rows.Scan(
+			&vote.ID,
+			&vote.ReportID,
+			&vote.UserID,
+			&vote.VoteType,
+			&vote.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		votes = append(votes, vote)
+	}
+	return votes, rows.Err()
+}
+
 func (r *VoteRepository) CreateVote(vote *model.ReportVote) error {
 	query := `
 		INSERT INTO report_votes (id, report_id, user_id, vote_type, created_at)
@@ -68,7 +104,7 @@ func (r *VoteRepository) UpdateVote(voteID uuid.UUID, voteType model.VoteType) e
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("vote not found")
+		return ErrVoteNotFound
 	}
 	return nil
 }
@@ -85,7 +121,7 @@ func (r *VoteRepository) DeleteVote(reportID, userID uuid.UUID) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("vote not found")
+		return ErrVoteNotFound
 	}
 	return nil
 }
@@ -102,7 +138,7 @@ func (r *VoteRepository) UpdateReportVoteScore(reportID uuid.UUID, delta int) er
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("report not found")
+		return ErrReportNotFound
 	}
 	return nil
 }
@@ -113,7 +149,7 @@ func (r *VoteRepository) GetReportVoteScore(reportID uuid.UUID) (int, error) {
 	err := r.db.QueryRow(query, reportID).Scan(&score)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return 0, fmt.Errorf("report not found")
+			return 0, ErrReportNotFound
 		}
 		return 0, err
 	}
@@ -223,7 +259,7 @@ func (r *VoteRepository) RemoveVoteWithTransaction(reportID, userID uuid.UUID) (
 	err = tx.QueryRow(query, reportID, userID).Scan(&voteType)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return 0, fmt.Errorf("no vote to remove")
+			return 0, ErrNoVoteToRemove
 		}
 		return 0, err
 	}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"report-service/internal/messaging"
+	"report-service/internal/model"
+	"report-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const maxAssignmentNoteLength = 500
+
+var (
+	ErrInvalidAssignment = errors.New("invalid assignment")
+	ErrAlreadyAssigned   = errors.New("report is already assigned, reassign it instead")
+	ErrNotAssigned       = errors.New("report is not assigned yet")
+	ErrAssigneeChanged   = errors.New("report was assigned to someone else meanwhile")
+	ErrReportClosed      = errors.New("report is completed or rejected")
+)
+
+type AssignmentService struct {
+	assignmentRepo *repository.AssignmentRepository
+	reportRepo     *repository.ReportRepository
+	outboxRepo     *repository.OutboxRepository
+}
+
+func NewAssignmentService(assignmentRepo *repository.AssignmentRepository, reportRepo *repository.ReportRepository, outboxRepo *repository.OutboxRepository) *AssignmentService {
+	return &AssignmentService{
+		assignmentRepo: assignmentRepo,
+		reportRepo:     reportRepo,
+		outboxRepo:     outboxRepo,
+	}
+}
+
+// GetOfficers lists who reports of the department can be assigned to: its
+// active members allowed to work on reports, admins included.
+func (s *AssignmentService) GetOfficers(perms model.Permissions, department *string) ([]model.Officer, error) {
+	if !perms.Has(model.PermReportAssign) || department == nil {
+		return nil, ErrAccessDenied
+	}
+	return s.assignmentRepo.FindOfficers(*department, model.PermReportStatusUpdate)
+}
+
+// Assign hands an unassigned report to an officer of its department.
+func (s *AssignmentService) Assign(reportID uuid.UUID, req *model.AssignReportRequest, perms model.Permissions, actor model.StatusActor, department *string) (*model.Assignment, error) {
+	return s.assign(reportID, req, false, perms, actor, department)
+}
+
+// Reassign moves an assigned report to another officer of its department.
+func (s *AssignmentService) Reassign(reportID uuid.UUID, req *model.AssignReportRequest, perms model.Permissions, actor model.StatusActor, department *string) (*model.Assignment, error) {
+	return s.assign(reportID, req, true, perms, actor, department)
+}
+
+// assign records the assignment and emits report.assigned through the
+// outbox, written in the same transaction.
+func (s *AssignmentService) assign(reportID uuid.UUID, req *model.AssignReportRequest, reassign bool, perms model.Permissions, actor model.StatusActor, department *string) (*model.Assignment, error) {
+	if !perms.Has(model.PermReportAssign) || department == nil {
+		return nil, ErrAccessDenied
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > maxAssignmentNoteLength {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", ErrInvalidAssignment, maxAssignmentNoteLength)
+	}
+
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Category.Department != *department {
+		return nil, ErrAccessDenied
+	}
+
+	// Merged reports follow their canonical report
+	if report.DuplicateOf != nil {
+		return nil, ErrReportMerged
+	}
+	if report.Status == model.StatusCompleted || report.Status == model.StatusRejected {
+		return nil, ErrReportClosed
+	}
+
+	if reassign && report.AssigneeID == nil {
+		return nil, ErrNotAssigned
+	}
+	if !reassign && report.AssigneeID != nil {
+		return nil, ErrAlreadyAssigned
+	}
+	if report.AssigneeID != nil && *report.AssigneeID == req.AssigneeID {
+		return nil, fmt.Errorf("%w: report is already assigned to this officer", ErrInvalidAssignment)
+	}
+
+	officer, err := s.assignmentRepo.FindOfficer(req.AssigneeID, *department, model.PermReportStatusUpdate)
+	if err != nil {
+		if errors.Is(err, repository.ErrOfficerNotFound) {
+			return nil, fmt.Errorf("%w: assignee must be an active officer of the report's department", ErrInvalidAssignment)
+		}
+		return nil, err
+	}
+
+	assignment := &model.Assignment{
+		ID:                   uuid.New(),
+		ReportID:             reportID,
+		AssigneeID:           &officer.ID,
+		AssigneeName:         &officer.Name,
+		PreviousAssigneeID:   report.AssigneeID,
+		PreviousAssigneeName: report.AssigneeName,
+		ActorID:              actor.ID,
+		ActorName:            actor.Name,
+		CreatedAt:            time.Now(),
+	}
+	if note != "" {
+		assignment.Note = &note
+	}
+
+	tx, err := s.assignmentRepo.BeginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	assigned, err := s.assignmentRepo.AssignInTransaction(tx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, ErrAssigneeChanged
+	}
+
+	msg := messaging.ReportAssignedMessage{
+		ReportID:    reportID.String(),
+		ReportTitle: report.Title,
+		AssigneeID:  officer.ID.String(),
+		Note:        note,
+		Timestamp:   assignment.CreatedAt.Unix(),
+	}
+	if actor.ID != nil {
+		msg.AssignedBy = actor.ID.String()
+	}
+	if report.AssigneeID != nil {
+		msg.PreviousAssigneeID = report.AssigneeID.String()
+	}
+	if err := s.outboxRepo.CreateInTransaction(tx, messaging.RoutingKeyReportAssigned, msg); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+// GetHistory returns a report's assignments, oldest first, to staff of its
+// department.
+func (s *AssignmentService) GetHistory(reportID uuid.UUID, perms model.Permissions, department *string) ([]model.Assignment, error) {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if !perms.Has(model.PermReportReadDepartment) || department == nil || report.Category.Department != *department {
+		return nil, ErrAccessDenied
+	}
+
+	return s.assignmentRepo.FindByReportID(reportID)
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"report-service/config"
+	"report-service/internal/imaging"
+	"report-service/internal/model"
+	"report-service/internal/repository"
+	"report-service/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxUploadBytes = 5 << 20
+	defaultMaxPerReport   = 5
+	defaultMaxDimension   = 2048
+	defaultThumbnailSize  = 320
+
+	attachmentURLPrefix       = "/api/v1/reports/attachments/"
+	publicAttachmentURLPrefix = "/api/v1/reports/public/attachments/"
+
+	contentTypePDF = "application/pdf"
+)
+
+var (
+	ErrInvalidAttachment  = errors.New("invalid attachment")
+	ErrUploadTooLarge     = errors.New("upload too large")
+	ErrAttachmentNotFound = repository.ErrAttachmentNotFound
+)
+
+type AttachmentService struct {
+	attachmentRepo *repository.AttachmentRepository
+	reportRepo     *repository.ReportRepository
+	store          storage.BlobStore
+	cfg            config.StorageConfig
+	anonConfig     config.AnonymousConfig
+}
+
+func NewAttachmentService(attachmentRepo *repository.AttachmentRepository, reportRepo *repository.ReportRepository, store storage.BlobStore, cfg config.StorageConfig, anonConfig config.AnonymousConfig) *AttachmentService {
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = defaultMaxUploadBytes
+	}
+	if cfg.MaxPerReport <= 0 {
+		cfg.MaxPerReport = defaultMaxPerReport
+	}
+	if cfg.MaxDimension <= 0 {
+		cfg.MaxDimension = defaultMaxDimension
+	}
+	if cfg.ThumbnailSize <= 0 {
+		cfg.ThumbnailSize = defaultThumbnailSize
+	}
+
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		reportRepo:     reportRepo,
+		store:          store,
+		cfg:            cfg,
+		anonConfig:     anonConfig,
+	}
+}
+
+func (s *AttachmentService) MaxUploadBytes() int64 {
+	return s.cfg.MaxUploadBytes
+}
+
+// Upload stores a citizen's photo or document. The upload belongs to no
+// report until one is filed with its ID.
+func (s *AttachmentService) Upload(userID string, data []byte) (*model.Attachment, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.storeFile(data)
+	if err != nil {
+		return nil, err
+	}
+	attachment.UploaderID = &uid
+	attachment.Role = model.AttachmentEvidence
+
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		s.deleteBlobs(attachment)
+		return nil, err
+	}
+
+	setAttachmentURLs(attachment, false)
+	return attachment, nil
+}
+
+// AddToReport uploads a file straight onto an existing report. The reporter
+// may add evidence; progress and resolution files come from staff of the
+// report's department.
+func (s *AttachmentService) AddToReport(reportID uuid.UUID, role model.AttachmentRole, data []byte, perms model.Permissions, userID string, department *string) (*model.Attachment, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidAttachment, role)
+	}
+
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role.ByStaff() {
+		if !perms.Has(model.PermReportStatusUpdate) || department == nil || report.Category.Department != *department {
+			return nil, ErrAccessDenied
+		}
+	} else if !isReporter(report, userID, s.anonConfig.Salt) {
+		return nil, ErrAccessDenied
+	}
+
+	// Merged reports follow their canonical report
+	if report.DuplicateOf != nil {
+		return nil, ErrReportMerged
+	}
+
+	count, err := s.attachmentRepo.CountByRole(reportID, role)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.cfg.MaxPerReport {
+		return nil, fmt.Errorf("%w: at most %d %s files per report", ErrInvalidAttachment, s.cfg.MaxPerReport, role)
+	}
+
+	attachment, err := s.storeFile(data)
+	if err != nil {
+		return nil, err
+	}
+	attachment.ReportID = &reportID
+	attachment.Role = role
+	// like the report itself, evidence on an anonymous report keeps no uploader
+	if role.ByStaff() || report.PrivacyLevel != model.PrivacyAnonymous {
+		attachment.UploaderID = &uid
+	}
+
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		s.deleteBlobs(attachment)
+		return nil, err
+	}
+
+	setAttachmentURLs(attachment, report.PrivacyLevel == model.PrivacyPublic)
+	return attachment, nil
+}
+
+// HasResolutionPhoto reports whether staff attached a resolution photo to
+// the report.
+func (s *AttachmentService) HasResolutionPhoto(reportID uuid.UUID) (bool, error) {
+	return s.attachmentRepo.HasPhoto(reportID, model.AttachmentResolution)
+}
+
+// storeFile checks an upload and writes it to the blob store. Photos are
+// cleaned and get a thumbnail; PDF documents are stored as uploaded.
+func (s *AttachmentService) storeFile(data []byte) (*model.Attachment, error) {
+	if int64(len(data)) > s.cfg.MaxUploadBytes {
+		return nil, ErrUploadTooLarge
+	}
+
+	id := uuid.New()
+	attachment := &model.Attachment{
+		ID:        id,
+		CreatedAt: time.Now(),
+	}
+
+	var content, thumbnail []byte
+	if http.DetectContentType(data) == contentTypePDF {
+		content = data
+		attachment.StorageKey = "attachments/" + id.String() + ".pdf"
+		attachment.ContentType = contentTypePDF
+	} else {
+		img, err := imaging.Process(data, s.cfg.MaxDimension, s.cfg.ThumbnailSize)
+		if err != nil {
+			if errors.Is(err, imaging.ErrUnsupported) || errors.Is(err, imaging.ErrTooLarge) {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidAttachment, err)
+			}
+			return nil, err
+		}
+
+		ext := ".jpg"
+		if img.ContentType == imaging.ContentTypePNG {
+			ext = ".png"
+		}
+
+		content, thumbnail = img.Data, img.Thumbnail
+		attachment.StorageKey = "attachments/" + id.String() + ext
+		attachment.ThumbnailKey = "thumbnails/" + id.String() + ".jpg"
+		attachment.ContentType = img.ContentType
+		attachment.Width = img.Width
+		attachment.Height = img.Height
+	}
+	attachment.SizeBytes = int64(len(content))
+
+	if err := s.store.Put(attachment.StorageKey, content, attachment.ContentType); err != nil {
+		return nil, err
+	}
+	if thumbnail != nil {
+		if err := s.store.Put(attachment.ThumbnailKey, thumbnail, imaging.ContentTypeJPEG); err != nil {
+			s.deleteBlobs(attachment)
+			return nil, err
+		}
+	}
+
+	return attachment, nil
+}
+
+// AttachInTransaction links uploads to a newly filed report as evidence. Each
+// one must be an unused upload of the reporter.
+func (s *AttachmentService) AttachInTransaction(tx *sql.Tx, report *model.Report, ids []uuid.UUID, uploaderID uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > s.cfg.MaxPerReport {
+		return fmt.Errorf("%w: at most %d evidence files per report", ErrInvalidAttachment, s.cfg.MaxPerReport)
+	}
+
+	attached, err := s.attachmentRepo.AttachInTransaction(tx, report.ID, unique, uploaderID, report.PrivacyLevel == model.PrivacyAnonymous)
+	if err != nil {
+		return err
+	}
+	if attached != int64(len(unique)) {
+		return fmt.Errorf("%w: attachments must be your own uploads not used by another report", ErrInvalidAttachment)
+	}
+
+	return nil
+}
+
+// ForReport lists the attachments of a report the caller is already allowed to
+// see.
+func (s *AttachmentService) ForReport(report *model.Report) ([]model.Attachment, error) {
+	attachments, err := s.attachmentRepo.FindByReportID(report.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range attachments {
+		setAttachmentURLs(&attachments[i], report.PrivacyLevel == model.PrivacyPublic)
+	}
+	return attachments, nil
+}
+
+// Open returns a photo, or its thumbnail, to a caller who may see its
+// report. Uploads not attached to a report yet are only visible to whoever
+// uploaded them.
+func (s *AttachmentService) Open(id uuid.UUID, thumbnail bool, perms model.Permissions, userID string, department *string) (io.ReadCloser, string, error) {
+	attachment, err := s.attachmentRepo.FindByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if attachment.ReportID == nil {
+		if attachment.UploaderID == nil || attachment.UploaderID.String() != userID {
+			return nil, "", ErrAccessDenied
+		}
+	} else {
+		report, err := s.reportRepo.FindByID(*attachment.ReportID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !canViewReport(report, perms, userID, department) {
+			return nil, "", ErrAccessDenied
+		}
+	}
+
+	return s.open(attachment, thumbnail)
+}
+
+// OpenPublic serves photos of public reports without a login, so they can
+// be embedded directly in pages and maps.
+func (s *AttachmentService) OpenPublic(id uuid.UUID, thumbnail bool) (io.ReadCloser, string, error) {
+	attachment, err := s.attachmentRepo.FindByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if attachment.ReportID == nil {
+		return nil, "", ErrAccessDenied
+	}
+
+	report, err := s.reportRepo.FindByID(*attachment.ReportID)
+	if err != nil {
+		return nil, "", err
+	}
+	if report.PrivacyLevel != model.PrivacyPublic {
+		return nil, "", ErrAccessDenied
+	}
+
+	return s.open(attachment, thumbnail)
+}
+
+func (s *AttachmentService) open(attachment *model.Attachment, thumbnail bool) (io.ReadCloser, string, error) {
+	key, contentType := attachment.StorageKey, attachment.ContentType
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, "", storage.ErrNotFound
+		}
+		key, contentType = attachment.ThumbnailKey, imaging.ContentTypeJPEG
+	}
+
+	body, err := s.store.Get(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, contentType, nil
+}
+
+func (s *AttachmentService) deleteBlobs(attachment *model.Attachment) {
+	for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(key); err != nil {
+			log.Printf("delete blob %s failed: %v", key, err)
+		}
+	}
+}
+
+// setAttachmentURLs points files of public reports at the public route,
+// which needs no login.
+func setAttachmentURLs(attachment *model.Attachment, public bool) {
+	prefix := attachmentURLPrefix
+	if public {
+		prefix = publicAttachmentURLPrefix
+	}
+	attachment.URL = prefix + attachment.ID.String()
+	if attachment.ThumbnailKey != "" {
+		attachment.ThumbnailURL = attachment.URL + "/thumbnail"
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"report-service/config"
+	"report-service/internal/messaging"
+	"report-service/internal/model"
+	"report-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const maxCommentLength = 2000
+
+var ErrInvalidComment = errors.New("invalid comment")
+
+type CommentService struct {
+	commentRepo *repository.CommentRepository
+	reportRepo  *repository.ReportRepository
+	outboxRepo  *repository.OutboxRepository
+	anonConfig  config.AnonymousConfig
+}
+
+func NewCommentService(commentRepo *repository.CommentRepository, reportRepo *repository.ReportRepository, outboxRepo *repository.OutboxRepository, anonConfig config.AnonymousConfig) *CommentService {
+	return &CommentService{
+		commentRepo: commentRepo,
+		reportRepo:  reportRepo,
+		outboxRepo:  outboxRepo,
+		anonConfig:  anonConfig,
+	}
+}
+
+// GetComments returns the comment threads of a report, oldest first.
+func (s *CommentService) GetComments(reportID uuid.UUID, perms model.Permissions, userID string, department *string) ([]model.Comment, error) {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if access := s.access(report, perms, userID, department); !access.allowed {
+		return nil, ErrAccessDenied
+	}
+
+	comments, err := s.commentRepo.FindByReportID(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	return commentThreads(comments), nil
+}
+
+// AddComment posts a comment or a reply. Comments by staff of the report's
+// department are marked as official responses. The reporter is notified
+// through the outbox, written in the same transaction as the comment.
+func (s *CommentService) AddComment(reportID uuid.UUID, req *model.CreateCommentRequest, perms model.Permissions, userID string, department *string) (*model.Comment, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		return nil, fmt.Errorf("%w: body must be 1 to %d characters", ErrInvalidComment, maxCommentLength)
+	}
+
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	access := s.access(report, perms, userID, department)
+	if !access.allowed {
+		return nil, ErrAccessDenied
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(*req.ParentID)
+		if err != nil && !errors.Is(err, repository.ErrCommentNotFound) {
+			return nil, err
+		}
+		if err != nil || parent.ReportID != reportID {
+			return nil, fmt.Errorf("%w: parent comment not found on this report", ErrInvalidComment)
+		}
+	}
+
+	comment := &model.Comment{
+		ID:         uuid.New(),
+		ReportID:   reportID,
+		ParentID:   req.ParentID,
+		AuthorID:   &uid,
+		IsReporter: access.isReporter,
+		IsOfficial: access.isStaff,
+		Body:       body,
+		CreatedAt:  time.Now(),
+	}
+
+	// The reporter of an anonymous report stays anonymous in its comments
+	if access.isReporter && report.PrivacyLevel == model.PrivacyAnonymous {
+		comment.AuthorID = nil
+	}
+
+	tx, err := s.commentRepo.BeginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.commentRepo.CreateInTransaction(tx, comment); err != nil {
+		return nil, err
+	}
+
+	msg := messaging.CommentCreatedMessage{
+		CommentID:   comment.ID.String(),
+		ReportID:    reportID.String(),
+		ReportTitle: report.Title,
+		IsReporter:  comment.IsReporter,
+		IsOfficial:  comment.IsOfficial,
+		Timestamp:   time.Now().Unix(),
+	}
+	if report.ReporterID != nil {
+		msg.ReporterID = report.ReporterID.String()
+	}
+	if comment.AuthorID != nil {
+		msg.AuthorID = comment.AuthorID.String()
+	}
+	if err := s.outboxRepo.CreateInTransaction(tx, messaging.RoutingKeyCommentCreated, msg); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+type commentAccess struct {
+	allowed    bool
+	isReporter bool
+	isStaff    bool
+}
+
+// access applies the report's privacy level to its comments: anyone may
+// read and comment on public reports, only the reporter and the report's
+// department on private and anonymous ones.
+func (s *CommentService) access(report *model.Report, perms model.Permissions, userID string, department *string) commentAccess {
+	var access commentAccess
+
+	access.isStaff = perms.Has(model.PermReportReadDepartment) &&
+		department != nil && report.Category.Department == *department
+
+	access.isReporter = isReporter(report, userID, s.anonConfig.Salt)
+
+	access.allowed = report.PrivacyLevel == model.PrivacyPublic || access.isStaff || access.isReporter
+	return access
+}
+
+// commentThreads nests replies under the comment they answer. comments must
+// be ordered oldest first, which keeps every thread in that order too.
+func commentThreads(comments []model.Comment) []model.Comment {
+	roots := []model.Comment{}
+	replies := make(map[uuid.UUID][]model.Comment)
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			replies[*comment.ParentID] = append(replies[*comment.ParentID], comment)
+		}
+	}
+
+	var nest func([]model.Comment) []model.Comment
+	nest = func(list []model.Comment) []model.Comment {
+		for i := range list {
+			list[i].Replies = nest(replies[list[i].ID])
+		}
+		return list
+	}
+
+	return nest(roots)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"report-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultReportPageSize = 20
+	maxReportPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// newReportPage turns the limit and cursor query parameters into a page
+// request. One row more than the limit is fetched so that reportList can
+// tell whether another page follows.
+func newReportPage(limit int, cursor string) (model.ReportPage, error) {
+	if limit <= 0 {
+		limit = defaultReportPageSize
+	}
+	if limit > maxReportPageSize {
+		limit = maxReportPageSize
+	}
+
+	page := model.ReportPage{Limit: limit + 1}
+	if cursor == "" {
+		return page, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return model.ReportPage{}, ErrInvalidCursor
+	}
+	var after model.ReportCursor
+	if err := json.Unmarshal(raw, &after); err != nil || after.ID == uuid.Nil {
+		return model.ReportPage{}, ErrInvalidCursor
+	}
+	page.After = &after
+
+	return page, nil
+}
+
+// reportList drops the look-ahead row of a page and, when there was one,
+// points the next cursor at the last report returned.
+func reportList(reports []model.Report, total int, page model.ReportPage) *model.ReportListResponse {
+	if reports == nil {
+		reports = []model.Report{}
+	}
+
+	response := &model.ReportListResponse{Total: total}
+	if len(reports) >= page.Limit {
+		reports = reports[:page.Limit-1]
+		last := reports[len(reports)-1]
+		after := model.ReportCursor{
+			VoteScore: last.VoteScore,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
+		if last.Rank != nil {
+			after.Rank = *last.Rank
+		}
+		if last.Distance != nil {
+			after.Distance = *last.Distance
+		}
+		after.SLADueAt = last.SLADueAt
+		raw, _ := json.Marshal(after)
+		response.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	response.Reports = reports
+
+	return response
+}
@@ -4,8 +4,11 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"strings"
 	"time"
 
 	"report-service/config"
@@ -16,28 +19,80 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrAccessDenied            = errors.New("access denied")
+	ErrReportNotFound          = repository.ErrReportNotFound
+	ErrCategoryNotFound        = repository.ErrCategoryNotFound
+	ErrInvalidDepartment       = errors.New("invalid department")
+	ErrInvalidLocation         = errors.New("invalid location")
+	ErrInvalidMerge            = errors.New("invalid merge")
+	ErrReportMerged            = errors.New("report has been merged into another report")
+	ErrInvalidTransition       = errors.New("invalid status transition")
+	ErrReasonRequired          = errors.New("a reason is required to reject or reopen a report")
+	ErrResolutionPhotoRequired = errors.New("a resolution photo is required to complete a report")
+	ErrInvalidSLA              = errors.New("invalid SLA targets")
+)
+
+const (
+	defaultNearbyRadius = 1000.0
+	maxNearbyRadius     = 50000.0
+
+	metresPerDegree = 111320.0
+)
+
+const (
+	defaultDuplicateRadius      = 250.0
+	defaultDuplicateSimilarity  = 0.35
+	defaultDuplicateWindowDays  = 30
+	defaultDuplicateSuggestions = 5
+)
+
 type ReportService struct {
-	reportRepo *repository.ReportRepository
-	outboxRepo *repository.OutboxRepository
-	anonConfig config.AnonymousConfig
-	rmq        *messaging.RabbitMQ
-	db         *sql.DB
+	reportRepo        *repository.ReportRepository
+	outboxRepo        *repository.OutboxRepository
+	attachmentService *AttachmentService
+	anonConfig        config.AnonymousConfig
+	dupConfig         config.DuplicateConfig
+	histConfig        config.HistoryConfig
+	workflowConfig    config.WorkflowConfig
+	rmq               *messaging.RabbitMQ
+	db                *sql.DB
 }
 
-func NewReportService(reportRepo *repository.ReportRepository, outboxRepo *repository.OutboxRepository, anonConfig config.AnonymousConfig, rmq *messaging.RabbitMQ, db *sql.DB) *ReportService {
+func NewReportService(reportRepo *repository.ReportRepository, outboxRepo *repository.OutboxRepository, attachmentService *AttachmentService, anonConfig config.AnonymousConfig, dupConfig config.DuplicateConfig, histConfig config.HistoryConfig, workflowConfig config.WorkflowConfig, rmq *messaging.RabbitMQ, db *sql.DB) *ReportService {
+	if dupConfig.RadiusMeters <= 0 {
+		dupConfig.RadiusMeters = defaultDuplicateRadius
+	}
+	if dupConfig.MinSimilarity <= 0 {
+		dupConfig.MinSimilarity = defaultDuplicateSimilarity
+	}
+	if dupConfig.WindowDays <= 0 {
+		dupConfig.WindowDays = defaultDuplicateWindowDays
+	}
+	if dupConfig.MaxSuggestions <= 0 {
+		dupConfig.MaxSuggestions = defaultDuplicateSuggestions
+	}
+
 	return &ReportService{
-		reportRepo: reportRepo,
-		outboxRepo: outboxRepo,
-		anonConfig: anonConfig,
-		rmq:        rmq,
-		db:         db,
+		reportRepo:        reportRepo,
+		outboxRepo:        outboxRepo,
+		attachmentService: attachmentService,
+		anonConfig:        anonConfig,
+		dupConfig:         dupConfig,
+		histConfig:        histConfig,
+		workflowConfig:    workflowConfig,
+		rmq:               rmq,
+		db:                db,
 	}
 }
 
-func (s *ReportService) CreateReport(req *model.CreateReportRequest, userID string, userName string) (*model.Report, error) {
+// CreateReport files a report and returns it together with open reports
+// that may describe the same problem. Suggestions only include reports the
+// citizen can already see.
+func (s *ReportService) CreateReport(req *model.CreateReportRequest, userID string, userName string) (*model.Report, []model.Report, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	report := &model.Report{
@@ -47,7 +102,6 @@ func (s *ReportService) CreateReport(req *model.CreateReportRequest, userID stri
 		CategoryID:   req.CategoryID,
 		LocationLat:  req.LocationLat,
 		LocationLng:  req.LocationLng,
-		PhotoURL:     req.PhotoURL,
 		PrivacyLevel: req.PrivacyLevel,
 		Status:       model.StatusPending,
 		VoteScore:    0,
@@ -57,15 +111,27 @@ func (s *ReportService) CreateReport(req *model.CreateReportRequest, userID stri
 
 	switch req.PrivacyLevel {
 	case model.PrivacyAnonymous:
-		hash := s.hashUserID(userID)
+		hash := reporterHash(userID, s.anonConfig.Salt)
 		report.ReporterHash = &hash
 	default:
 		report.ReporterID = &uid
 		report.ReporterName = &userName
 	}
 
-	if err := s.reportRepo.Create(report); err != nil {
-		return nil, err
+	tx, err := s.reportRepo.BeginTx()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.reportRepo.CreateInTransaction(tx, report); err != nil {
+		return nil, nil, err
+	}
+	if err := s.attachmentService.AttachInTransaction(tx, report, req.AttachmentIDs, uid); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
 	}
 
 	if s.outboxRepo != nil {
@@ -95,11 +161,33 @@ func (s *ReportService) CreateReport(req *model.CreateReportRequest, userID stri
 
 	report.ReporterHash = nil
 
-	return report, nil
+	// A failed lookup must not fail a report that is already filed
+	suggestions, err := s.findDuplicateCandidates(report, &uid)
+	if err != nil {
+		log.Printf("duplicate lookup for %s failed: %v", report.ID, err)
+	}
+
+	return report, suggestions, nil
 }
 
-func (s *ReportService) GetReports(userRole string, department *string) (*model.ReportListResponse, error) {
-	reports, err := s.reportRepo.FindAll(userRole, department)
+func (s *ReportService) GetReports(perms model.Permissions, department *string, filter model.ReportFilter, limit int, cursor string) (*model.ReportListResponse, error) {
+	// Department staff see everything filed under their department and may
+	// narrow it down by assignee or SLA state, anyone else gets the public
+	// feed.
+	var scope *string
+	if perms.Has(model.PermReportReadDepartment) {
+		if department == nil {
+			return nil, ErrAccessDenied
+		}
+		scope = department
+	}
+
+	page, err := newReportPage(limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, total, err := s.reportRepo.FindAll(scope, filter, page)
 	if err != nil {
 		return nil, err
 	}
@@ -109,79 +197,180 @@ func (s *ReportService) GetReports(userRole string, department *string) (*model.
 			reports[i].ReporterID = nil
 			reports[i].ReporterName = nil
 		}
+		if scope == nil {
+			hideStaffDetails(&reports[i])
+		}
 	}
 
-	return &model.ReportListResponse{
-		Reports: reports,
-		Total:   len(reports),
-	}, nil
+	return reportList(reports, total, page), nil
 }
 
-func (s *ReportService) GetPublicReports() (*model.ReportListResponse, error) {
-	reports, err := s.reportRepo.GetPublicReports()
+func (s *ReportService) GetPublicReports(limit int, cursor string) (*model.ReportListResponse, error) {
+	page, err := newReportPage(limit, cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	if reports == nil {
-		reports = []model.Report{}
+	reports, total, err := s.reportRepo.GetPublicReports(page)
+	if err != nil {
+		return nil, err
 	}
 
-	return &model.ReportListResponse{
-		Reports: reports,
-		Total:   len(reports),
-	}, nil
+	return reportList(reports, total, page), nil
+}
+
+// GetNearbyReports lists public reports within radius metres of a point,
+// nearest first. The radius defaults to 1 km and is capped at 50 km.
+func (s *ReportService) GetNearbyReports(lat, lng, radius float64, limit int, cursor string) (*model.ReportListResponse, error) {
+	if !validCoordinates(lat, lng) {
+		return nil, ErrInvalidLocation
+	}
+	if radius <= 0 {
+		radius = defaultNearbyRadius
+	}
+	if radius > maxNearbyRadius {
+		radius = maxNearbyRadius
+	}
+
+	page, err := newReportPage(limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	box := boundingBox(lat, lng, radius)
+	reports, total, err := s.reportRepo.FindNearby(lat, lng, radius, box, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return reportList(reports, total, page), nil
+}
+
+// GetReportsWithin lists public reports inside a map bounding box, highest
+// voted first. Boxes crossing the antimeridian are not supported.
+func (s *ReportService) GetReportsWithin(box model.BoundingBox, limit int, cursor string) (*model.ReportListResponse, error) {
+	if !validCoordinates(box.MinLat, box.MinLng) || !validCoordinates(box.MaxLat, box.MaxLng) ||
+		box.MinLat > box.MaxLat || box.MinLng > box.MaxLng {
+		return nil, ErrInvalidLocation
+	}
+
+	page, err := newReportPage(limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, total, err := s.reportRepo.FindWithin(box, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return reportList(reports, total, page), nil
 }
 
-func (s *ReportService) GetMyReports(userID string) (*model.ReportListResponse, error) {
+func (s *ReportService) GetMyReports(userID string, limit int, cursor string) (*model.ReportListResponse, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	reports, err := s.reportRepo.FindByReporterID(uid)
+	page, err := newReportPage(limit, cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	if reports == nil {
-		reports = []model.Report{}
+	reports, total, err := s.reportRepo.FindByReporterID(uid, page)
+	if err != nil {
+		return nil, err
 	}
 
-	return &model.ReportListResponse{
-		Reports: reports,
-		Total:   len(reports),
-	}, nil
+	return reportList(reports, total, page), nil
 }
 
-func (s *ReportService) GetReportByID(id uuid.UUID, userRole string, userID string, department *string) (*model.Report, error) {
-	report, err := s.reportRepo.FindByID(id)
+// GetAssignedReports lists the open reports assigned to an officer: their
+// work queue.
+func (s *ReportService) GetAssignedReports(userID string, limit int, cursor string) (*model.ReportListResponse, error) {
+	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if userRole != "warga" && department != nil {
-		if report.Category.Department != *department {
-			return nil, fmt.Errorf("access denied")
-		}
+	page, err := newReportPage(limit, cursor)
+	if err != nil {
+		return nil, err
 	}
 
-	if userRole == "warga" {
-		if report.PrivacyLevel != model.PrivacyPublic {
-			if report.ReporterID == nil || report.ReporterID.String() != userID {
-				return nil, fmt.Errorf("access denied")
-			}
-		}
+	reports, total, err := s.reportRepo.FindByAssignee(uid, page)
+	if err != nil {
+		return nil, err
+	}
+
+	hideAnonymousReporters(reports)
+	return reportList(reports, total, page), nil
+}
+
+func (s *ReportService) GetReportByID(id uuid.UUID, perms model.Permissions, userID string, department *string) (*model.Report, error) {
+	report, err := s.reportRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canViewReport(report, perms, userID, department) {
+		return nil, ErrAccessDenied
 	}
 
 	if report.PrivacyLevel == model.PrivacyAnonymous {
 		report.ReporterID = nil
 		report.ReporterName = nil
 	}
+	if !perms.Has(model.PermReportReadDepartment) {
+		hideStaffDetails(report)
+	}
+
+	report.Attachments, err = s.attachmentService.ForReport(report)
+	if err != nil {
+		return nil, err
+	}
 
 	return report, nil
 }
 
+// canViewReport tells whether a caller may see a report: department staff
+// see their department's reports, citizens public reports and their own.
+func canViewReport(report *model.Report, perms model.Permissions, userID string, department *string) bool {
+	if perms.Has(model.PermReportReadDepartment) {
+		return department != nil && report.Category.Department == *department
+	}
+	return report.PrivacyLevel == model.PrivacyPublic ||
+		(report.ReporterID != nil && report.ReporterID.String() == userID)
+}
+
+// GetStatusHistory returns the status timeline of a report to anyone allowed
+// to see the report. Unless configured otherwise, citizens do not learn
+// which admin made each change.
+func (s *ReportService) GetStatusHistory(id uuid.UUID, perms model.Permissions, userID string, department *string) ([]model.StatusHistoryEntry, error) {
+	report, err := s.reportRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewReport(report, perms, userID, department) {
+		return nil, ErrAccessDenied
+	}
+
+	history, err := s.reportRepo.FindStatusHistory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.histConfig.HideActors && !perms.Has(model.PermReportReadDepartment) {
+		for i := range history {
+			history[i].ActorID = nil
+			history[i].ActorName = nil
+		}
+	}
+
+	return history, nil
+}
+
 func (s *ReportService) UpdateReport(reportID uuid.UUID, userID string, req *model.UpdateReportRequest) (*model.Report, error) {
 	report, err := s.reportRepo.FindByID(reportID)
 	if err != nil {
@@ -199,25 +388,62 @@ func (s *ReportService) UpdateReport(reportID uuid.UUID, userID string, req *mod
 	return s.reportRepo.FindByID(reportID)
 }
 
-func (s *ReportService) UpdateReportStatus(reportID uuid.UUID, status model.ReportStatus, department *string) error {
+// UpdateReportStatus moves a report along the status workflow. Rejecting or
+// reopening a report needs a reason, which is stored with the report and
+// passed on to the reporter.
+func (s *ReportService) UpdateReportStatus(reportID uuid.UUID, status model.ReportStatus, reason string, actor model.StatusActor, department *string) error {
 	report, err := s.reportRepo.FindByID(reportID)
 	if err != nil {
 		return err
 	}
 
-	if department != nil && report.Category.Department != *department {
-		return fmt.Errorf("access denied")
+	if department == nil || report.Category.Department != *department {
+		return ErrAccessDenied
+	}
+
+	// Merged reports follow their canonical report
+	if report.DuplicateOf != nil {
+		return ErrReportMerged
 	}
 
-	if err := s.reportRepo.UpdateStatus(reportID, status); err != nil {
+	if !report.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, report.Status, status)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" && report.Status.RequiresReason(status) {
+		return ErrReasonRequired
+	}
+
+	if status == model.StatusCompleted && s.workflowConfig.RequireResolutionPhoto {
+		hasPhoto, err := s.attachmentService.HasResolutionPhoto(reportID)
+		if err != nil {
+			return err
+		}
+		if !hasPhoto {
+			return ErrResolutionPhotoRequired
+		}
+	}
+
+	var statusReason *string
+	if reason != "" {
+		statusReason = &reason
+	}
+
+	updated, err := s.reportRepo.UpdateStatus(reportID, report.Status, status, statusReason, actor)
+	if err != nil {
 		return err
 	}
+	if !updated {
+		return fmt.Errorf("%w: report is no longer %s", ErrInvalidTransition, report.Status)
+	}
 
 	if s.outboxRepo != nil {
 		msg := messaging.StatusUpdateMessage{
 			ReportID:    reportID.String(),
 			ReportTitle: report.Title,
 			NewStatus:   string(status),
+			Reason:      reason,
 			Timestamp:   time.Now().Unix(),
 		}
 
@@ -225,6 +451,21 @@ func (s *ReportService) UpdateReportStatus(reportID uuid.UUID, status model.Repo
 			msg.ReporterID = report.ReporterID.String()
 		}
 
+		duplicates, err := s.reportRepo.FindDuplicates(reportID)
+		if err != nil {
+			log.Printf("duplicates of %s: %v", reportID, err)
+		}
+		for _, duplicate := range duplicates {
+			d := messaging.DuplicateReport{
+				ReportID:    duplicate.ID.String(),
+				ReportTitle: duplicate.Title,
+			}
+			if duplicate.ReporterID != nil {
+				d.ReporterID = duplicate.ReporterID.String()
+			}
+			msg.Duplicates = append(msg.Duplicates, d)
+		}
+
 		if err := s.outboxRepo.Create(messaging.RoutingKeyStatusUpdate, msg); err != nil {
 			log.Printf("outbox save failed: %v", err)
 		}
@@ -233,45 +474,153 @@ func (s *ReportService) UpdateReportStatus(reportID uuid.UUID, status model.Repo
 	return nil
 }
 
+// GetDuplicates returns the reports already merged into a report and, while
+// it is not merged itself, other open reports of its department that may be
+// duplicates of it.
+func (s *ReportService) GetDuplicates(reportID uuid.UUID, department *string) (*model.DuplicatesResponse, error) {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if department == nil || report.Category.Department != *department {
+		return nil, ErrAccessDenied
+	}
+
+	merged, err := s.reportRepo.FindDuplicates(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []model.Report
+	if report.DuplicateOf == nil {
+		candidates, err = s.findDuplicateCandidates(report, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hideAnonymousReporters(merged)
+	hideAnonymousReporters(candidates)
+
+	return &model.DuplicatesResponse{
+		Duplicates: merged,
+		Candidates: candidates,
+	}, nil
+}
+
+// MergeReports marks reports as duplicates of a canonical report in the same
+// department. Their votes carry over to the canonical report and their
+// reporters are notified of its status changes from then on.
+func (s *ReportService) MergeReports(canonicalID uuid.UUID, duplicateIDs []uuid.UUID, actor model.StatusActor, department *string) (*model.Report, error) {
+	canonical, err := s.reportRepo.FindByID(canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if department == nil || canonical.Category.Department != *department {
+		return nil, ErrAccessDenied
+	}
+
+	if canonical.DuplicateOf != nil {
+		return nil, fmt.Errorf("%w: report %s is itself merged into %s", ErrInvalidMerge, canonicalID, canonical.DuplicateOf)
+	}
+	if len(duplicateIDs) == 0 {
+		return nil, fmt.Errorf("%w: no duplicate_ids given", ErrInvalidMerge)
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, id := range duplicateIDs {
+		if id == canonicalID {
+			return nil, fmt.Errorf("%w: a report cannot be merged into itself", ErrInvalidMerge)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		duplicate, err := s.reportRepo.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate.Category.Department != canonical.Category.Department {
+			return nil, fmt.Errorf("%w: report %s belongs to another department", ErrInvalidMerge, id)
+		}
+		if duplicate.DuplicateOf != nil {
+			return nil, fmt.Errorf("%w: report %s is already merged into %s", ErrInvalidMerge, id, duplicate.DuplicateOf)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := s.reportRepo.Merge(canonicalID, ids, actor); err != nil {
+		return nil, err
+	}
+
+	report, err := s.reportRepo.FindByID(canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	if report.PrivacyLevel == model.PrivacyAnonymous {
+		report.ReporterID = nil
+		report.ReporterName = nil
+	}
+
+	return report, nil
+}
+
 func (s *ReportService) GetCategories() ([]model.Category, error) {
 	return s.reportRepo.GetAllCategories()
 }
 
-func (s *ReportService) SearchPublicReports(search string, categoryID *int) (*model.ReportListResponse, error) {
-	reports, err := s.reportRepo.SearchPublicReports(search, categoryID)
+func (s *ReportService) SearchPublicReports(search string, categoryID *int, limit int, cursor string) (*model.ReportListResponse, error) {
+	page, err := newReportPage(limit, cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	if reports == nil {
-		reports = []model.Report{}
+	reports, total, err := s.reportRepo.SearchPublicReports(search, categoryID, page)
+	if err != nil {
+		return nil, err
 	}
 
-	return &model.ReportListResponse{
-		Reports: reports,
-		Total:   len(reports),
-	}, nil
+	return reportList(reports, total, page), nil
 }
 
-func (s *ReportService) SearchMyReports(userID string, search string, categoryID *int) (*model.ReportListResponse, error) {
+func (s *ReportService) SearchMyReports(userID string, search string, categoryID *int, limit int, cursor string) (*model.ReportListResponse, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	reports, err := s.reportRepo.SearchMyReports(uid, search, categoryID)
+	page, err := newReportPage(limit, cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	if reports == nil {
-		reports = []model.Report{}
+	reports, total, err := s.reportRepo.SearchMyReports(uid, search, categoryID, page)
+	if err != nil {
+		return nil, err
 	}
 
-	return &model.ReportListResponse{
-		Reports: reports,
-		Total:   len(reports),
-	}, nil
+	return reportList(reports, total, page), nil
+}
+
+// UpdateCategorySLA sets a category's acknowledgement and resolution
+// targets. The SLA job gives reports of the category due dates from them.
+func (s *ReportService) UpdateCategorySLA(id int, req *model.UpdateCategorySLARequest) (*model.Category, error) {
+	ack, resolution := req.AckTargetHours, req.ResolutionTargetHours
+	if (ack != nil && *ack <= 0) || (resolution != nil && *resolution <= 0) {
+		return nil, fmt.Errorf("%w: targets must be positive hours", ErrInvalidSLA)
+	}
+	if ack != nil && resolution != nil && *ack > *resolution {
+		return nil, fmt.Errorf("%w: acknowledgement target exceeds resolution target", ErrInvalidSLA)
+	}
+
+	if err := s.reportRepo.UpdateCategorySLA(id, ack, resolution); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.GetCategoryByID(id)
 }
 
 func (s *ReportService) GetOrCreateCategory(name, department string) (*model.Category, error) {
@@ -283,11 +632,99 @@ func (s *ReportService) GetOrCreateCategory(name, department string) (*model.Cat
 		return existing, nil
 	}
 
+	exists, err := s.reportRepo.DepartmentExists(department)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrInvalidDepartment
+	}
+
 	return s.reportRepo.CreateCategory(name, department)
 }
 
-func (s *ReportService) hashUserID(userID string) string {
-	data := userID + s.anonConfig.Salt
+// reporterHash links anonymous reports to their author for abuse detection
+// without storing who the author is.
+func reporterHash(userID, salt string) string {
+	data := userID + salt
 	hash := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(hash[:])
 }
+
+// isReporter tells whether userID filed the report, recognising the
+// reporter of an anonymous report by their hash.
+func isReporter(report *model.Report, userID, salt string) bool {
+	if report.ReporterID != nil {
+		return report.ReporterID.String() == userID
+	}
+	return report.ReporterHash != nil && *report.ReporterHash == reporterHash(userID, salt)
+}
+
+// findDuplicateCandidates looks for open reports similar to report. viewer
+// limits the results to reports that citizen may see; nil means no limit.
+func (s *ReportService) findDuplicateCandidates(report *model.Report, viewer *uuid.UUID) ([]model.Report, error) {
+	q := model.DuplicateQuery{
+		ReportID:      report.ID,
+		Title:         report.Title,
+		Description:   report.Description,
+		CategoryID:    report.CategoryID,
+		RadiusMeters:  s.dupConfig.RadiusMeters,
+		MinSimilarity: s.dupConfig.MinSimilarity,
+		Since:         report.CreatedAt.AddDate(0, 0, -s.dupConfig.WindowDays),
+		VisibleTo:     viewer,
+		Limit:         s.dupConfig.MaxSuggestions,
+	}
+
+	if report.LocationLat != nil && report.LocationLng != nil {
+		q.Lat = report.LocationLat
+		q.Lng = report.LocationLng
+		q.Box = boundingBox(*report.LocationLat, *report.LocationLng, s.dupConfig.RadiusMeters)
+	}
+
+	candidates, err := s.reportRepo.FindDuplicateCandidates(q)
+	if err != nil {
+		return nil, err
+	}
+
+	hideAnonymousReporters(candidates)
+	return candidates, nil
+}
+
+// hideStaffDetails withholds who handles a report, and its SLA deadlines,
+// from citizens.
+func hideStaffDetails(report *model.Report) {
+	report.AssigneeID = nil
+	report.AssigneeName = nil
+	report.AssignedAt = nil
+	report.SLAState = ""
+	report.AckDueAt = nil
+	report.ResolutionDueAt = nil
+	report.SLADueAt = nil
+}
+
+func hideAnonymousReporters(reports []model.Report) {
+	for i := range reports {
+		if reports[i].PrivacyLevel == model.PrivacyAnonymous {
+			reports[i].ReporterID = nil
+			reports[i].ReporterName = nil
+		}
+	}
+}
+
+// boundingBox returns the box around a circle of radius metres. A degree of
+// longitude shrinks towards the poles, so the box widens to still contain the
+// whole circle.
+func boundingBox(lat, lng, radius float64) model.BoundingBox {
+	latSpan := radius / metresPerDegree
+	lngSpan := math.Min(180, radius/(metresPerDegree*math.Cos(lat*math.Pi/180)))
+	return model.BoundingBox{
+		MinLat: lat - latSpan,
+		MinLng: lng - lngSpan,
+		MaxLat: lat + latSpan,
+		MaxLng: lng + lngSpan,
+	}
+}
+
+func validCoordinates(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
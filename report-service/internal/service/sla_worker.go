@@ -0,0 +1,144 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"report-service/config"
+	"report-service/internal/messaging"
+	"report-service/internal/model"
+	"report-service/internal/repository"
+)
+
+const (
+	defaultSLACheckInterval = 60
+	defaultSLAAtRiskRatio   = 0.75
+	defaultSLABatchSize     = 100
+)
+
+// SLAWorker periodically gives new reports their SLA deadlines, escalates
+// missed ones through report.sla.breached and refreshes every report's SLA
+// state.
+type SLAWorker struct {
+	slaRepo        *repository.SLARepository
+	assignmentRepo *repository.AssignmentRepository
+	outboxRepo     *repository.OutboxRepository
+	cfg            config.SLAConfig
+	done           chan struct{}
+	wg             sync.WaitGroup
+}
+
+func NewSLAWorker(slaRepo *repository.SLARepository, assignmentRepo *repository.AssignmentRepository, outboxRepo *repository.OutboxRepository, cfg config.SLAConfig) *SLAWorker {
+	if cfg.CheckIntervalSeconds <= 0 {
+		cfg.CheckIntervalSeconds = defaultSLACheckInterval
+	}
+	if cfg.AtRiskRatio <= 0 || cfg.AtRiskRatio >= 1 {
+		cfg.AtRiskRatio = defaultSLAAtRiskRatio
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultSLABatchSize
+	}
+
+	return &SLAWorker{
+		slaRepo:        slaRepo,
+		assignmentRepo: assignmentRepo,
+		outboxRepo:     outboxRepo,
+		cfg:            cfg,
+		done:           make(chan struct{}),
+	}
+}
+
+func (w *SLAWorker) Start() {
+	w.wg.Add(1)
+	go w.loop()
+	log.Println("sla: started")
+}
+
+func (w *SLAWorker) loop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(time.Duration(w.cfg.CheckIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	w.check()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+func (w *SLAWorker) check() {
+	if n, err := w.slaRepo.SetDueDates(); err != nil {
+		log.Printf("sla: set due dates: %v", err)
+	} else if n > 0 {
+		log.Printf("sla: set due dates of %d reports", n)
+	}
+
+	breaches, err := w.slaRepo.FindUnescalatedBreaches(w.cfg.BatchSize)
+	if err != nil {
+		log.Printf("sla: find breaches: %v", err)
+	}
+	for _, breach := range breaches {
+		if err := w.escalate(breach); err != nil {
+			log.Printf("sla: escalate %s %s: %v", breach.ReportID, breach.Kind, err)
+		}
+	}
+
+	if _, err := w.slaRepo.UpdateStates(w.cfg.AtRiskRatio); err != nil {
+		log.Printf("sla: update states: %v", err)
+	}
+}
+
+// escalate records a breach and emits report.sla.breached in the same
+// transaction, addressed to the assignee and the department's admins.
+func (w *SLAWorker) escalate(breach model.SLABreach) error {
+	admins, err := w.assignmentRepo.FindOfficers(breach.Department, model.PermReportAssign)
+	if err != nil {
+		return err
+	}
+
+	tx, err := w.slaRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	marked, err := w.slaRepo.MarkBreachedInTransaction(tx, breach.ReportID, breach.Kind)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return nil
+	}
+
+	msg := messaging.SLABreachedMessage{
+		ReportID:    breach.ReportID.String(),
+		ReportTitle: breach.ReportTitle,
+		Department:  breach.Department,
+		Kind:        string(breach.Kind),
+		DueAt:       breach.DueAt.Unix(),
+		Timestamp:   time.Now().Unix(),
+	}
+	if breach.AssigneeID != nil {
+		msg.AssigneeID = breach.AssigneeID.String()
+	}
+	for _, admin := range admins {
+		msg.EscalateTo = append(msg.EscalateTo, admin.ID.String())
+	}
+	if err := w.outboxRepo.CreateInTransaction(tx, messaging.RoutingKeySLABreached, msg); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (w *SLAWorker) Stop() {
+	close(w.done)
+	w.wg.Wait()
+	log.Println("sla: stopped")
+}
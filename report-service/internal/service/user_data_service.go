@@ -0,0 +1,67 @@
+package service
+
+import (
+	"report-service/config"
+	"report-service/internal/model"
+	"report-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// UserDataService serves auth-service's personal data export and account
+// erasure. It is only reachable through the /internal routes.
+type UserDataService struct {
+	reportRepo  *repository.ReportRepository
+	voteRepo    *repository.VoteRepository
+	commentRepo *repository.CommentRepository
+	anonConfig  config.AnonymousConfig
+}
+
+func NewUserDataService(reportRepo *repository.ReportRepository, voteRepo *repository.VoteRepository, commentRepo *repository.CommentRepository, anonConfig config.AnonymousConfig) *UserDataService {
+	return &UserDataService{
+		reportRepo:  reportRepo,
+		voteRepo:    voteRepo,
+		commentRepo: commentRepo,
+		anonConfig:  anonConfig,
+	}
+}
+
+// Export includes the user's anonymous reports too, found through their
+// reporter hash.
+func (s *UserDataService) Export(userID uuid.UUID) (*model.UserDataExport, error) {
+	reports, err := s.reportRepo.FindByReporter(userID, reporterHash(userID.String(), s.anonConfig.Salt))
+	if err != nil {
+		return nil, err
+	}
+	if reports == nil {
+		reports = []model.Report{}
+	}
+
+	votes, err := s.voteRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.FindByAuthorID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserDataExport{
+		Reports:  reports,
+		Votes:    votes,
+		Comments: comments,
+	}, nil
+}
+
+// Anonymise turns the user's named reports into anonymous-style ones before
+// the account is deleted. Votes are left to the users foreign key cascade;
+// vote scores already counted stay as they are. Comments stay too, with the
+// foreign key clearing their author.
+func (s *UserDataService) Anonymise(userID uuid.UUID) (*model.AnonymiseResponse, error) {
+	count, err := s.reportRepo.AnonymiseReporter(userID, reporterHash(userID.String(), s.anonConfig.Salt))
+	if err != nil {
+		return nil, err
+	}
+	return &model.AnonymiseResponse{AnonymisedReports: count}, nil
+}
@@ -46,13 +46,18 @@ func (s *VoteService) CastVote(reportIDStr, userIDStr string, voteType model.Vot
 	// hanya report publik yang bisa di-vote
 	report, err := s.reportRepo.FindByID(reportID)
 	if err != nil {
-		return nil, fmt.Errorf("report not found")
+		return nil, err
 	}
 
 	if report.PrivacyLevel != model.PrivacyPublic {
 		return nil, fmt.Errorf("can only vote on public reports")
 	}
 
+	// votes on a merged report belong to its canonical report
+	if report.DuplicateOf != nil {
+		return nil, ErrReportMerged
+	}
+
 	newScore, err := s.voteRepo.VoteWithTransaction(reportID, userID, voteType)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"report-service/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps uploaded files by key. Keys are generated by
+// report-service and use "/" to separate path segments.
+type BlobStore interface {
+	Put(key string, data []byte, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+func NewBlobStore(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.LocalPath)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"report-service/internal/messaging"
 	"report-service/internal/repository"
 	"report-service/internal/service"
+	"report-service/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
@@ -60,35 +61,79 @@ func main() {
 	reportRepo := repository.NewReportRepository(db)
 	voteRepo := repository.NewVoteRepository(db)
 	outboxRepo := repository.NewOutboxRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	assignmentRepo := repository.NewAssignmentRepository(db)
+	slaRepo := repository.NewSLARepository(db)
+
+	blobStore, err := storage.NewBlobStore(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to set up attachment storage: %v", err)
+	}
 
 	outboxWorker := messaging.NewOutboxWorker(outboxRepo, rmq)
 	outboxWorker.Start()
 
-	reportService := service.NewReportService(reportRepo, outboxRepo, cfg.Anonymous, rmq, db)
+	slaWorker := service.NewSLAWorker(slaRepo, assignmentRepo, outboxRepo, cfg.SLA)
+	slaWorker.Start()
+
+	attachmentService := service.NewAttachmentService(attachmentRepo, reportRepo, blobStore, cfg.Storage, cfg.Anonymous)
+	reportService := service.NewReportService(reportRepo, outboxRepo, attachmentService, cfg.Anonymous, cfg.Duplicates, cfg.History, cfg.Workflow, rmq, db)
 	voteService := service.NewVoteService(voteRepo, reportRepo, outboxRepo, rmq)
+	commentService := service.NewCommentService(commentRepo, reportRepo, outboxRepo, cfg.Anonymous)
+	assignmentService := service.NewAssignmentService(assignmentRepo, reportRepo, outboxRepo)
+	userDataService := service.NewUserDataService(reportRepo, voteRepo, commentRepo, cfg.Anonymous)
 
 	reportHandler := handler.NewReportHandler(reportService)
 	voteHandler := handler.NewVoteHandler(voteService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	assignmentHandler := handler.NewAssignmentHandler(assignmentService)
+	internalHandler := handler.NewInternalHandler(userDataService, cfg.Internal.Token)
 
 	r := gin.Default()
 
 	r.GET("/health", reportHandler.Health)
 
 	r.GET("/public", reportHandler.GetPublicReports)
+	r.GET("/public/nearby", reportHandler.GetNearbyReports)
+	r.GET("/public/within", reportHandler.GetReportsWithin)
+	r.GET("/public/attachments/:id", attachmentHandler.GetPublicAttachment)
+	r.GET("/public/attachments/:id/thumbnail", attachmentHandler.GetPublicThumbnail)
 	r.GET("/categories", reportHandler.GetCategories)
 	r.POST("/categories", reportHandler.CreateCategory)
+	r.PUT("/categories/:id/sla", reportHandler.UpdateCategorySLA)
+
+	r.POST("/attachments", attachmentHandler.Upload)
+	r.GET("/attachments/:id", attachmentHandler.GetAttachment)
+	r.GET("/attachments/:id/thumbnail", attachmentHandler.GetThumbnail)
 
 	r.POST("/", reportHandler.CreateReport)
 	r.GET("/", reportHandler.GetReports)
 	r.GET("/my", reportHandler.GetMyReports)
+	r.GET("/assigned", reportHandler.GetAssignedReports)
+	r.GET("/officers", assignmentHandler.GetOfficers)
 	r.GET("/:id", reportHandler.GetReportByID)
 	r.PUT("/:id", reportHandler.UpdateReport)
 	r.PATCH("/:id/status", reportHandler.UpdateStatus)
+	r.GET("/:id/history", reportHandler.GetStatusHistory)
+	r.GET("/:id/duplicates", reportHandler.GetDuplicates)
+	r.POST("/:id/merge", reportHandler.MergeReports)
+	r.POST("/:id/attachments", attachmentHandler.AddToReport)
+	r.POST("/:id/assign", assignmentHandler.Assign)
+	r.POST("/:id/reassign", assignmentHandler.Reassign)
+	r.GET("/:id/assignments", assignmentHandler.GetHistory)
 
 	r.POST("/:id/vote", voteHandler.CastVote)
 	r.DELETE("/:id/vote", voteHandler.RemoveVote)
 	r.GET("/:id/vote", voteHandler.GetVote)
 
+	r.GET("/:id/comments", commentHandler.GetComments)
+	r.POST("/:id/comments", commentHandler.AddComment)
+
+	r.GET("/internal/users/:id/export", internalHandler.ExportUserData)
+	r.POST("/internal/users/:id/anonymise", internalHandler.AnonymiseUser)
+
 	r.GET("/admin/outbox/stats", func(c *gin.Context) {
 		stats, err := outboxWorker.GetStats()
 		if err != nil {
@@ -104,6 +149,7 @@ func main() {
 	go func() {
 		<-quit
 		log.Println("\nShutdown signal received...")
+		slaWorker.Stop()
 		outboxWorker.Stop()
 		log.Println("Report service stopped gracefully")
 		os.Exit(0)
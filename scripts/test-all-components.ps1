@@ -310,20 +310,10 @@ if ($token -and $reportId) {
 ################################################################################
 Write-TestHeader "TEST 7: ADMIN STATUS UPDATE"
 
-# Register admin
-$adminEmail = "admin_$(Get-Random)@test.com"
+# Login admin (admin accounts can't self-register, use the seeded one)
 try {
-    $adminRegBody = @{
-        email = $adminEmail
-        password = "password123"
-        name = "Admin User"
-        role = "admin_infrastruktur"
-    } | ConvertTo-Json
-    
-    $null = Invoke-RestMethod -Uri "$API_BASE/auth/register" -Method POST -ContentType "application/json" -Body $adminRegBody -TimeoutSec 10
-    
     $adminLoginBody = @{
-        email = $adminEmail
+        email = "admin_infrastruktur@test.com"
         password = "password123"
     } | ConvertTo-Json
     
@@ -343,6 +333,7 @@ if ($adminToken -and $reportId) {
             "Content-Type" = "application/json"
             "X-User-ID" = $adminLogin.user.id
             "X-User-Role" = "admin_infrastruktur"
+            "X-User-Permissions" = "report.status.update"
         }
         
         $statusResponse = Invoke-RestMethod -Uri "$REPORT_SERVICE/$reportId/status" -Method PATCH -Headers $adminHeaders -Body $statusBody -TimeoutSec 10
@@ -458,6 +449,7 @@ if ($token -and $reportId -and $adminToken) {
             "Content-Type" = "application/json"
             "X-User-ID" = $adminLogin.user.id
             "X-User-Role" = "admin_infrastruktur"
+            "X-User-Permissions" = "report.status.update"
         }
         $null = Invoke-RestMethod -Uri "$REPORT_SERVICE/$reportId/status" -Method PATCH -Headers $adminHeaders -Body $statusBody -TimeoutSec 10
         Write-Host "       Triggered status update to 'completed'" -ForegroundColor Gray
@@ -324,25 +324,19 @@ if ($voteAttempts -lt $totalVotes) {
 ################################################################################
 Write-Banner "PHASE 4: STATUS UPDATES" "Red"
 
-$adminEmail = "admin_load_$(Get-Random)@test.com"
-$adminBody = @{
-    email = $adminEmail
-    password = "password123"
-    name = "Load Test Admin"
-    role = "admin_infrastruktur"
-} | ConvertTo-Json
+# Admin accounts can't self-register, use the seeded one
+$adminEmail = "admin_infrastruktur@test.com"
 
 $adminToken = $null
 $adminUserId = $null
 
 try {
-    $null = Invoke-RestMethod -Uri "$API_BASE/auth/register" -Method POST -ContentType "application/json" -Body $adminBody -TimeoutSec 30
     $adminLogin = Invoke-RestMethod -Uri "$API_BASE/auth/login" -Method POST -ContentType "application/json" -Body (@{ email = $adminEmail; password = "password123" } | ConvertTo-Json) -TimeoutSec 30
     $adminToken = $adminLogin.token
     $adminUserId = $adminLogin.user.id
-    Write-Host "Admin user created" -ForegroundColor Green
+    Write-Host "Admin logged in" -ForegroundColor Green
 } catch {
-    Write-Host "Failed to create admin" -ForegroundColor Red
+    Write-Host "Failed to login admin" -ForegroundColor Red
 }
 
 if ($adminToken) {
@@ -362,6 +356,7 @@ if ($adminToken) {
             "Content-Type" = "application/json"
             "X-User-ID" = $adminUserId
             "X-User-Role" = "admin_infrastruktur"
+            "X-User-Permissions" = "report.status.update"
         }
         
         $body = @{ status = ($statuses | Get-Random) } | ConvertTo-Json
@@ -14,11 +14,8 @@ $headers = @{ "Authorization" = "Bearer $TOKEN"; "Content-Type" = "application/j
 
 Write-Host "[OK] User token obtained" -ForegroundColor Green
 
-# Admin login
-try {
-    $null = Invoke-RestMethod -Uri "$API_BASE/auth/register" -Method POST -ContentType "application/json" -Body '{"email":"adminextreme@test.com","password":"password123","name":"Admin Extreme","role":"admin_infrastruktur"}' -ErrorAction SilentlyContinue
-} catch {}
-$adminLogin = Invoke-RestMethod -Uri "$API_BASE/auth/login" -Method POST -ContentType "application/json" -Body '{"email":"adminextreme@test.com","password":"password123"}'
+# Admin login (admin accounts can't self-register, use the seeded one)
+$adminLogin = Invoke-RestMethod -Uri "$API_BASE/auth/login" -Method POST -ContentType "application/json" -Body '{"email":"admin_infrastruktur@test.com","password":"password123"}'
 $ADMIN_TOKEN = $adminLogin.token
 $adminHeaders = @{ "Authorization" = "Bearer $ADMIN_TOKEN"; "Content-Type" = "application/json" }
 
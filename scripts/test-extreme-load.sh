@@ -31,15 +31,10 @@ if [ -z "$TOKEN" ]; then
 fi
 echo -e "${GREEN}[OK] User token obtained${NC}"
 
-# Register admin (ignore errors if already exists)
-curl -s -X POST "$API_BASE/auth/register" \
-  -H "Content-Type: application/json" \
-  -d '{"email":"adminextreme@test.com","password":"password123","name":"Admin Extreme","role":"admin_infrastruktur"}' > /dev/null 2>&1
-
-# Login admin
+# Login admin (admin accounts can't self-register, use the seeded one)
 ADMIN_LOGIN_RESPONSE=$(curl -s -X POST "$API_BASE/auth/login" \
   -H "Content-Type: application/json" \
-  -d '{"email":"adminextreme@test.com","password":"password123"}')
+  -d '{"email":"admin_infrastruktur@test.com","password":"password123"}')
 
 ADMIN_TOKEN=$(echo "$ADMIN_LOGIN_RESPONSE" | grep -o '"token":"[^"]*"' | cut -d'"' -f4)
 